@@ -0,0 +1,289 @@
+package gohtmx
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/TheWozard/gohtmx/element"
+)
+
+// ErrAccessibility is reported by Validate for every violation of an AccessibilityRule with SeverityError.
+var ErrAccessibility = errors.New("accessibility")
+
+// Severity is how a violation of an AccessibilityRule is reported.
+type Severity int
+
+const (
+	// SeverityDefault uses the Severity of the AccessibilityRule.
+	SeverityDefault Severity = iota
+	// SeverityOff does not check the rule.
+	SeverityOff
+	// SeverityWarning logs violations through the Logger of the Page.
+	SeverityWarning
+	// SeverityError fails validation for violations.
+	SeverityError
+)
+
+// AccessibilityRule checks a single accessibility requirement of the tags of a path.
+type AccessibilityRule struct {
+	// Name identifies the rule for configuring its Severity and for Suppress.
+	Name string
+	// Severity is used unless configured otherwise by Accessibility.
+	Severity Severity
+	// Check is called for every tag of a path in document order, returning a description of any violation.
+	Check func(c *AccessibilityContext, t *element.Tag) string
+}
+
+// AccessibilityContext is the state of an AccessibilityRule while checking the tags of a single path.
+type AccessibilityContext struct {
+	// Root is the Element of the path being checked.
+	Root element.Element
+	// Ancestors are the tags containing the current tag, outer most first.
+	Ancestors []*element.Tag
+	// State can be used by the rule to keep track of previous tags.
+	State any
+}
+
+// Accessibility configures the accessibility rules checked by Page.Validate.
+type Accessibility struct {
+	// Rules are the rules to check. Defaults to AccessibilityRules.
+	Rules []AccessibilityRule
+	// Severity overrides the Severity of rules by name.
+	Severity map[string]Severity
+}
+
+// AccessibilityRules are the built in rules, covering the basics of WCAG.
+var AccessibilityRules = []AccessibilityRule{
+	{Name: "img-alt", Severity: SeverityError, Check: checkImgAlt},
+	{Name: "button-name", Severity: SeverityError, Check: checkButtonName},
+	{Name: "input-label", Severity: SeverityError, Check: checkInputLabel},
+	{Name: "heading-order", Severity: SeverityWarning, Check: checkHeadingOrder},
+	{Name: "html-lang", Severity: SeverityError, Check: checkHTMLLang},
+}
+
+// Suppress disables the named AccessibilityRules for the Content.
+type Suppress struct {
+	Rules   []string
+	Content Component
+}
+
+func (s Suppress) Init(p *Page) (element.Element, error) {
+	return suppressElement{rules: s.Rules, content: p.Init(s.Content)}, nil
+}
+
+// suppressElement marks the rules suppressed within its content.
+type suppressElement struct {
+	rules   []string
+	content element.Element
+}
+
+func (s suppressElement) Render(w io.Writer) error {
+	if s.content == nil {
+		return nil
+	}
+	return s.content.Render(w)
+}
+
+func (s suppressElement) Validate() error {
+	if s.content == nil {
+		return nil
+	}
+	return s.content.Validate()
+}
+
+func (s suppressElement) GetTags() []*element.Tag {
+	if s.content == nil {
+		return nil
+	}
+	return s.content.GetTags()
+}
+
+func (s suppressElement) Children() []element.Element {
+	if s.content == nil {
+		return nil
+	}
+	return []element.Element{s.content}
+}
+
+// check checks the rules against the Element. Violations of rules with SeverityError are returned, and all other
+// violations are passed to warn.
+func (a *Accessibility) check(e element.Element, warn func(err error)) []error {
+	rules := a.Rules
+	if rules == nil {
+		rules = AccessibilityRules
+	}
+	type active struct {
+		rule     AccessibilityRule
+		severity Severity
+		context  *AccessibilityContext
+	}
+	checks := []active{}
+	for _, rule := range rules {
+		severity := a.Severity[rule.Name]
+		if severity == SeverityDefault {
+			severity = rule.Severity
+		}
+		if severity == SeverityDefault {
+			severity = SeverityWarning
+		}
+		if severity == SeverityOff {
+			continue
+		}
+		checks = append(checks, active{rule: rule, severity: severity, context: &AccessibilityContext{Root: e}})
+	}
+
+	errs := []error{}
+	suppressed := map[string]int{}
+	ancestors := []*element.Tag{}
+	_ = element.Walk(e, func(e element.Element, path []string) error {
+		switch e := e.(type) {
+		case suppressElement:
+			for _, rule := range e.rules {
+				suppressed[rule]++
+			}
+		case *element.Tag:
+			for _, check := range checks {
+				if suppressed[check.rule.Name] > 0 {
+					continue
+				}
+				check.context.Ancestors = ancestors
+				message := check.rule.Check(check.context, e)
+				if message == "" {
+					continue
+				}
+				err := element.PathError{
+					Path: path,
+					Err:  fmt.Errorf("%w: %s: %s", ErrAccessibility, check.rule.Name, message),
+				}
+				if check.severity == SeverityError {
+					errs = append(errs, err)
+				} else {
+					warn(err)
+				}
+			}
+			ancestors = append(ancestors, e)
+		}
+		return nil
+	}, func(e element.Element, path []string) error {
+		switch e := e.(type) {
+		case suppressElement:
+			for _, rule := range e.rules {
+				suppressed[rule]--
+			}
+		case *element.Tag:
+			ancestors = ancestors[:len(ancestors)-1]
+		}
+		return nil
+	})
+	return errs
+}
+
+// hiddenFromAssistiveTech returns if the tag is not presented to assistive technology.
+func hiddenFromAssistiveTech(t *element.Tag) bool {
+	hidden, _ := t.Attr("aria-hidden")
+	role, _ := t.Attr("role")
+	return hidden == "true" || role == "presentation" || role == "none"
+}
+
+// labelled returns if the tag has an accessible name through aria attributes or a title.
+func labelled(t *element.Tag) bool {
+	for _, name := range []string{"aria-label", "aria-labelledby", "title"} {
+		if value, ok := t.Attr(name); ok && strings.TrimSpace(value) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func checkImgAlt(_ *AccessibilityContext, t *element.Tag) string {
+	if t.Name != "img" || hiddenFromAssistiveTech(t) {
+		return ""
+	}
+	if _, ok := t.Attr("alt"); !ok {
+		return "<img> is missing alt text, use an empty alt for decorative images"
+	}
+	return ""
+}
+
+func checkButtonName(_ *AccessibilityContext, t *element.Tag) string {
+	if t.Name != "button" || hiddenFromAssistiveTech(t) || labelled(t) {
+		return ""
+	}
+	hasText := false
+	_ = element.Walk(t.Content, func(e element.Element, _ []string) error {
+		switch e := e.(type) {
+		case element.Raw:
+			hasText = hasText || strings.TrimSpace(string(e)) != ""
+		case *element.Tag:
+			if hiddenFromAssistiveTech(e) {
+				return element.SkipChildren
+			}
+			if alt, ok := e.Attr("alt"); e.Name == "img" && ok && alt != "" || labelled(e) {
+				hasText = true
+			}
+		}
+		return nil
+	}, nil)
+	if !hasText {
+		return "<button> has no accessible text"
+	}
+	return ""
+}
+
+func checkInputLabel(c *AccessibilityContext, t *element.Tag) string {
+	switch t.Name {
+	case "input":
+		// Buttons are named by their value, and hidden inputs are never presented.
+		switch kind, _ := t.Attr("type"); kind {
+		case "hidden", "submit", "reset", "button", "image":
+			return ""
+		}
+	case "select", "textarea":
+	default:
+		return ""
+	}
+	if hiddenFromAssistiveTech(t) || labelled(t) {
+		return ""
+	}
+	for _, ancestor := range c.Ancestors {
+		if ancestor.Name == "label" {
+			return ""
+		}
+	}
+	if id, ok := t.Attr("id"); ok {
+		labels, err := element.Query(c.Root, `label[for="`+id+`"]`)
+		if err == nil && len(labels) > 0 {
+			return ""
+		}
+	}
+	return fmt.Sprintf("<%s> has no associated label", t.Name)
+}
+
+func checkHeadingOrder(c *AccessibilityContext, t *element.Tag) string {
+	if len(t.Name) != 2 || t.Name[0] != 'h' {
+		return ""
+	}
+	level, err := strconv.Atoi(t.Name[1:])
+	if err != nil || level < 1 || level > 6 {
+		return ""
+	}
+	previous, _ := c.State.(int)
+	c.State = level
+	if previous > 0 && level > previous+1 {
+		return fmt.Sprintf("<%s> skips heading levels after <h%d>", t.Name, previous)
+	}
+	return ""
+}
+
+func checkHTMLLang(_ *AccessibilityContext, t *element.Tag) string {
+	if t.Name != "html" {
+		return ""
+	}
+	if lang, ok := t.Attr("lang"); !ok || strings.TrimSpace(lang) == "" {
+		return "<html> is missing lang, set Document.Lang"
+	}
+	return ""
+}
@@ -0,0 +1,126 @@
+package gohtmx_test
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/TheWozard/gohtmx"
+	"github.com/TheWozard/gohtmx/attributes"
+	"github.com/TheWozard/gohtmx/element"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessibility(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		severity  map[string]gohtmx.Severity
+		component gohtmx.Component
+		errs      []string
+		warnings  []string
+	}{
+		{
+			desc: "valid",
+			component: gohtmx.Document{Lang: "en", Body: gohtmx.Fragment{
+				gohtmx.H{Level: 1, Content: gohtmx.Raw("title")},
+				gohtmx.H{Level: 2, Content: gohtmx.Raw("section")},
+				gohtmx.Img{Src: "/logo.png", Alt: "logo"},
+				gohtmx.Img{Src: "/divider.png", Attrs: attributes.New().String("role", "presentation")},
+				gohtmx.Button{Content: gohtmx.Raw("save")},
+				gohtmx.Button{Attr: attributes.New().String("aria-label", "close")},
+				gohtmx.Tag{Name: "label", Attrs: attributes.New().String("for", "name"), Content: gohtmx.Raw("Name")},
+				gohtmx.Input{ID: "name", Name: "name"},
+				gohtmx.Tag{Name: "label", Content: gohtmx.Input{Name: "email"}},
+				gohtmx.Input{Type: "hidden", Name: "token"},
+			}},
+		},
+		{
+			desc:      "missing alt",
+			component: gohtmx.Img{Src: "/logo.png"},
+			errs:      []string{"img(0) accessibility: img-alt: <img> is missing alt text, use an empty alt for decorative images"},
+		},
+		{
+			desc: "button without text",
+			component: gohtmx.Button{Content: gohtmx.Span{
+				Attrs:   attributes.New().String("aria-hidden", "true"),
+				Content: gohtmx.Raw("x"),
+			}},
+			errs: []string{"button(0) accessibility: button-name: <button> has no accessible text"},
+		},
+		{
+			desc:      "input without label",
+			component: gohtmx.Div{Content: gohtmx.Input{ID: "name", Name: "name"}},
+			errs:      []string{"div(0).input(0) accessibility: input-label: <input> has no associated label"},
+		},
+		{
+			desc:      "missing lang",
+			component: gohtmx.Document{},
+			errs:      []string{"html(0) accessibility: html-lang: <html> is missing lang, set Document.Lang"},
+		},
+		{
+			desc: "heading order",
+			component: gohtmx.Fragment{
+				gohtmx.H{Level: 1, Content: gohtmx.Raw("title")},
+				gohtmx.H{Level: 3, Content: gohtmx.Raw("section")},
+			},
+			warnings: []string{"h3(1) accessibility: heading-order: <h3> skips heading levels after <h1>"},
+		},
+		{
+			desc:     "configured severity",
+			severity: map[string]gohtmx.Severity{"img-alt": gohtmx.SeverityWarning, "heading-order": gohtmx.SeverityError},
+			component: gohtmx.Fragment{
+				gohtmx.Img{Src: "/logo.png"},
+				gohtmx.H{Level: 2, Content: gohtmx.Raw("title")},
+				gohtmx.H{Level: 4, Content: gohtmx.Raw("section")},
+			},
+			errs:     []string{"h4(2) accessibility: heading-order: <h4> skips heading levels after <h2>"},
+			warnings: []string{"img(0) accessibility: img-alt: <img> is missing alt text, use an empty alt for decorative images"},
+		},
+		{
+			desc:      "rule off",
+			severity:  map[string]gohtmx.Severity{"img-alt": gohtmx.SeverityOff},
+			component: gohtmx.Img{Src: "/logo.png"},
+		},
+		{
+			desc: "suppressed",
+			component: gohtmx.Fragment{
+				gohtmx.Suppress{Rules: []string{"img-alt"}, Content: gohtmx.Img{Src: "/logo.png"}},
+				gohtmx.Img{Src: "/other.png"},
+			},
+			errs: []string{"img(1) accessibility: img-alt: <img> is missing alt text, use an empty alt for decorative images"},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			logs := bytes.NewBuffer(nil)
+			p := gohtmx.NewPage()
+			p.Logger = slog.New(slog.NewTextHandler(logs, &slog.HandlerOptions{
+				ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+					if a.Key != "violation" {
+						return slog.Attr{}
+					}
+					return a
+				},
+			}))
+			p.Accessibility = &gohtmx.Accessibility{Severity: tC.severity}
+			p.Add(tC.component)
+			errs := p.Validate()
+			if len(tC.errs) == 0 {
+				require.Nil(t, errs)
+			} else {
+				messages := []string{}
+				for _, err := range errs["/"].(interface{ Unwrap() []error }).Unwrap() {
+					require.ErrorIs(t, err, gohtmx.ErrAccessibility)
+					require.IsType(t, element.PathError{}, err)
+					messages = append(messages, err.Error())
+				}
+				require.Equal(t, tC.errs, messages)
+			}
+			warnings := ""
+			for _, warning := range tC.warnings {
+				warnings += `violation="` + warning + `"` + "\n"
+			}
+			require.Equal(t, warnings, logs.String())
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package gohtmx
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/TheWozard/gohtmx/element"
+)
+
+// Anchor designates the tag of a Component that is used when the Component is referenced, such as the target of a
+// Swap. This allows Components rendering multiple tags to be referenced. The anchor is the tag matching the Selector
+// within the Content, or the single tag of the Content if the Selector is empty. An outerHTML Swap of the Component
+// only renders the anchor, as the rest of the Component is not replaced.
+type Anchor struct {
+	Selector string
+	Content  Component
+}
+
+func (a Anchor) Init(p *Page) (element.Element, error) {
+	return anchorElement{selector: a.Selector, content: p.Init(a.Content)}, nil
+}
+
+// anchorElement marks the anchor within an initialized Reference.
+type anchorElement struct {
+	selector string
+	content  element.Element
+}
+
+func (a anchorElement) tag() (*element.Tag, error) {
+	var tags []*element.Tag
+	if a.selector != "" {
+		var err error
+		tags, err = element.Query(a.content, a.selector)
+		if err != nil {
+			return nil, err
+		}
+	} else if a.content != nil {
+		tags = a.content.GetTags()
+	}
+	if len(tags) != 1 {
+		return nil, fmt.Errorf(`failed to find anchor, expected 1 tag but found %d`, len(tags))
+	}
+	return tags[0], nil
+}
+
+func (a anchorElement) Render(w io.Writer) error {
+	if a.content == nil {
+		return nil
+	}
+	return a.content.Render(w)
+}
+
+func (a anchorElement) Validate() error {
+	if a.content == nil {
+		return nil
+	}
+	return a.content.Validate()
+}
+
+func (a anchorElement) GetTags() []*element.Tag {
+	if a.content == nil {
+		return nil
+	}
+	return a.content.GetTags()
+}
+
+func (a anchorElement) Children() []element.Element {
+	if a.content == nil {
+		return nil
+	}
+	return []element.Element{a.content}
+}
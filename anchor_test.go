@@ -0,0 +1,115 @@
+package gohtmx_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TheWozard/gohtmx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnchor(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		wrap     string
+		target   gohtmx.Component
+		expected string
+		swapped  string
+		err      string
+	}{
+		{
+			desc: "anchor",
+			target: gohtmx.Fragment{
+				gohtmx.H{Level: 2, Content: gohtmx.Raw("title")},
+				gohtmx.Anchor{Content: gohtmx.Div{Content: gohtmx.Raw("content")}},
+			},
+			expected: `<h2>title</h2><div id="gohtmx_0">content</div>` +
+				`<button hx-post="/interaction" hx-swap="outerHTML" hx-target="#gohtmx_0" type="button">update</button>`,
+			swapped: `<div id="gohtmx_0">content</div>`,
+		},
+		{
+			desc: "anchor selector",
+			target: gohtmx.Anchor{Selector: "div.content", Content: gohtmx.Fragment{
+				gohtmx.Div{Classes: []string{"header"}},
+				gohtmx.Div{Classes: []string{"content"}},
+			}},
+			expected: `<div class="header"></div><div class="content" id="gohtmx_0"></div>` +
+				`<button hx-post="/interaction" hx-swap="outerHTML" hx-target="#gohtmx_0" type="button">update</button>`,
+			swapped: `<div class="content" id="gohtmx_0"></div>`,
+		},
+		{
+			desc: "wrap",
+			wrap: "section",
+			target: gohtmx.Fragment{
+				gohtmx.Div{Content: gohtmx.Raw("a")},
+				gohtmx.Div{Content: gohtmx.Raw("b")},
+			},
+			expected: `<section id="gohtmx_0"><div>a</div><div>b</div></section>` +
+				`<button hx-post="/interaction" hx-swap="outerHTML" hx-target="#gohtmx_0" type="button">update</button>`,
+			swapped: `<section id="gohtmx_0"><div>a</div><div>b</div></section>`,
+		},
+		{
+			desc: "multiple tags",
+			target: gohtmx.Fragment{
+				gohtmx.Div{Content: gohtmx.Raw("a")},
+				gohtmx.Div{Content: gohtmx.Raw("b")},
+			},
+			err: "multiple tags in initialized Reference",
+		},
+		{
+			desc:   "anchor selector without match",
+			target: gohtmx.Anchor{Selector: "form", Content: gohtmx.Div{}},
+			err:    "failed to find anchor, expected 1 tag but found 0",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			p := gohtmx.NewPage()
+			p.AnchorWrap = tC.wrap
+			interaction := gohtmx.NewInteraction("interaction")
+			p.Add(gohtmx.Fragment{
+				interaction,
+				interaction.Swap().Update(tC.target),
+				interaction.Trigger().Target(gohtmx.Button{Content: gohtmx.Raw("update")}),
+			})
+			h, err := p.Build()
+			if tC.err != "" {
+				require.ErrorContains(t, err, tC.err)
+				return
+			}
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+			require.Equal(t, tC.expected, w.Body.String())
+
+			w = httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/interaction", nil)
+			r.Header.Set("HX-Request", "true")
+			h.ServeHTTP(w, r)
+			require.Equal(t, tC.swapped, w.Body.String())
+		})
+	}
+}
+
+func TestAnchorOutOfBand(t *testing.T) {
+	p := gohtmx.NewPage()
+	interaction := gohtmx.NewInteraction("interaction")
+	p.Add(gohtmx.Fragment{
+		interaction,
+		interaction.Swap().OutOfBand().Update(gohtmx.Fragment{
+			gohtmx.H{Level: 2, Content: gohtmx.Raw("title")},
+			gohtmx.Anchor{Content: gohtmx.Div{Content: gohtmx.Raw("content")}},
+		}),
+		interaction.Trigger().Target(gohtmx.Button{Content: gohtmx.Raw("update")}),
+	})
+	h, err := p.Build()
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/interaction", nil)
+	r.Header.Set("HX-Request", "true")
+	h.ServeHTTP(w, r)
+	require.Equal(t, `<div hx-swap-oob="outerHTML" id="gohtmx_0">content</div>`, w.Body.String())
+}
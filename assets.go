@@ -0,0 +1,198 @@
+package gohtmx
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"text/template/parse"
+	"time"
+
+	"github.com/TheWozard/gohtmx/element"
+)
+
+// NewAssets creates new Assets serving the files of the fs.FS under the path.
+func NewAssets(path string, fsys fs.FS) *Assets {
+	return &Assets{Path: path, FS: fsys}
+}
+
+// Assets serves static files with content hash fingerprinted URLs. As the URL of a file changes whenever its content
+// does, files are served with long lived immutable cache headers. The files of the FS are expected not to change.
+type Assets struct {
+	// Path is the path the files are served under. It must end in a "/".
+	Path string
+	// FS contains the files to serve.
+	FS fs.FS
+
+	mu     sync.Mutex
+	hashes map[string]string
+}
+
+// URL returns the fingerprinted URL of the named file.
+func (a *Assets) URL(name string) (string, error) {
+	hash, err := a.hash(name)
+	if err != nil {
+		return "", err
+	}
+	return path.Join(a.Path, hash, name), nil
+}
+
+// hash returns the content hash of the named file. Hashes are computed once and cached.
+func (a *Assets) hash(name string) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if hash, ok := a.hashes[name]; ok {
+		return hash, nil
+	}
+	raw, err := fs.ReadFile(a.FS, name)
+	if err != nil {
+		return "", fmt.Errorf("failed to read asset '%s': %w", name, err)
+	}
+	sum := sha256.Sum256(raw)
+	hash := hex.EncodeToString(sum[:8])
+	if a.hashes == nil {
+		a.hashes = map[string]string{}
+	}
+	a.hashes[name] = hash
+	return hash, nil
+}
+
+// ServeHTTP serves the file of a fingerprinted URL. URLs with an outdated hash are not found.
+func (a *Assets) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	hash, name, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, a.Path), "/")
+	if !ok || !fs.ValidPath(name) {
+		http.NotFound(w, r)
+		return
+	}
+	current, err := a.hash(name)
+	if err != nil || current != hash {
+		http.NotFound(w, r)
+		return
+	}
+	raw, err := fs.ReadFile(a.FS, name)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("ETag", `"`+hash+`"`)
+	http.ServeContent(w, r, name, time.Time{}, bytes.NewReader(raw))
+}
+
+// mount serves the Assets from the page at the path of the Assets.
+func (p *Page) mount(a *Assets) {
+	if p == nil || a == nil {
+		return
+	}
+	p.modify(a.Path, func(request *Request) {
+		request.Endpoint = a
+	})
+}
+
+// Static serves the files of the fs.FS under the path with fingerprinted URLs. Files are referenced by templates
+// through {{asset "app.css"}}, or through the Asset Component. Referencing a missing file fails to build the Page.
+// Static should be called on the root Page before adding any Components.
+func (p *Page) Static(path string, fsys fs.FS) *Assets {
+	if p == nil {
+		return nil
+	}
+	p.Assets = NewAssets(path, fsys)
+	p.mount(p.Assets)
+	p.funcs(template.FuncMap{"asset": p.Assets.URL})
+	return p.Assets
+}
+
+// Asset renders the fingerprinted URL of the named file served by Page.Static.
+type Asset string
+
+func (a Asset) Init(p *Page) (element.Element, error) {
+	if p.Assets == nil {
+		return nil, fmt.Errorf("no static assets for '%s'", a)
+	}
+	url, err := p.Assets.URL(string(a))
+	if err != nil {
+		return nil, err
+	}
+	return element.Raw(url), nil
+}
+
+// validateAssets checks every file referenced through the asset template func in the rendered request exists.
+// Templates that fail to parse are left to be reported when building.
+func validateAssets(a *Assets, raw []byte) error {
+	tree := parse.New("assets")
+	tree.Mode = parse.SkipFuncCheck
+	_, err := tree.Parse(string(raw), "", "", map[string]*parse.Tree{})
+	if err != nil {
+		return nil
+	}
+	return checkAssets(a, tree.Root)
+}
+
+// checkAssets checks every file referenced through the asset template func with a constant name exists.
+func checkAssets(a *Assets, node parse.Node) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			err := checkAssets(a, child)
+			if err != nil {
+				return err
+			}
+		}
+	case *parse.ActionNode:
+		return checkAssets(a, n.Pipe)
+	case *parse.TemplateNode:
+		return checkAssets(a, n.Pipe)
+	case *parse.IfNode:
+		return checkAssetsBranch(a, n.BranchNode)
+	case *parse.RangeNode:
+		return checkAssetsBranch(a, n.BranchNode)
+	case *parse.WithNode:
+		return checkAssetsBranch(a, n.BranchNode)
+	case *parse.PipeNode:
+		if n == nil {
+			return nil
+		}
+		for _, cmd := range n.Cmds {
+			err := checkAssets(a, cmd)
+			if err != nil {
+				return err
+			}
+		}
+	case *parse.CommandNode:
+		if ident, ok := n.Args[0].(*parse.IdentifierNode); ok && ident.Ident == "asset" && len(n.Args) == 2 {
+			if name, ok := n.Args[1].(*parse.StringNode); ok {
+				if a == nil {
+					return fmt.Errorf("no static assets for '%s'", name.Text)
+				}
+				_, err := a.URL(name.Text)
+				return err
+			}
+		}
+		for _, arg := range n.Args {
+			err := checkAssets(a, arg)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func checkAssetsBranch(a *Assets, n parse.BranchNode) error {
+	for _, node := range []parse.Node{n.Pipe, n.List, n.ElseList} {
+		err := checkAssets(a, node)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
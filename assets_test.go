@@ -0,0 +1,113 @@
+package gohtmx_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/TheWozard/gohtmx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssets(t *testing.T) {
+	a := gohtmx.NewAssets("/assets/", fstest.MapFS{"app.css": {Data: []byte("body {}")}})
+	url, err := a.URL("app.css")
+	require.NoError(t, err)
+	require.Regexp(t, `^/assets/[0-9a-f]{16}/app\.css$`, url)
+	_, err = a.URL("missing.css")
+	require.Error(t, err)
+
+	w := httptest.NewRecorder()
+	a.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "body {}", w.Body.String())
+	require.Equal(t, "public, max-age=31536000, immutable", w.Header().Get("Cache-Control"))
+
+	w = httptest.NewRecorder()
+	a.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/assets/0000000000000000/app.css", nil))
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestDocumentHTMX(t *testing.T) {
+	p := gohtmx.NewPage()
+	p.HTMX = gohtmx.NewAssets("/vendor/", fstest.MapFS{
+		"htmx.min.js": {Data: []byte("htmx")},
+		"ext/sse.js":  {Data: []byte("sse")},
+	})
+	p.Add(gohtmx.Document{HTMX: true, Extensions: []string{"sse"}})
+	h, err := p.Build()
+	require.NoError(t, err)
+
+	htmx, err := p.HTMX.URL("htmx.min.js")
+	require.NoError(t, err)
+	sse, err := p.HTMX.URL("ext/sse.js")
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, `<!DOCTYPE html><html><head>`+
+		`<script src="`+htmx+`"></script><script src="`+sse+`"></script>`+
+		`</head><body></body></html>`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, sse, nil))
+	require.Equal(t, "sse", w.Body.String())
+
+	p = gohtmx.NewPage()
+	p.HTMX = gohtmx.NewAssets("/vendor/", fstest.MapFS{})
+	p.Add(gohtmx.Document{HTMX: true})
+	_, err = p.Build()
+	require.ErrorContains(t, err, "htmx.min.js is not vendored, run go generate")
+}
+
+func TestStatic(t *testing.T) {
+	p := gohtmx.NewPage()
+	assets := p.Static("/static/", fstest.MapFS{"app.css": {Data: []byte("body {}")}})
+	p.Add(gohtmx.Fragment{
+		gohtmx.Raw(`<link href="{{asset "app.css"}}">`),
+		gohtmx.Asset("app.css"),
+	})
+	h, err := p.Build()
+	require.NoError(t, err)
+
+	url, err := assets.URL("app.css")
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, `<link href="`+url+`">`+url, w.Body.String())
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+	require.Equal(t, "body {}", w.Body.String())
+	require.Equal(t, "public, max-age=31536000, immutable", w.Header().Get("Cache-Control"))
+
+	tests := []struct {
+		name      string
+		component gohtmx.Component
+	}{
+		{name: "component", component: gohtmx.Asset("missing.css")},
+		{name: "template", component: gohtmx.Raw(`{{if true}}{{asset "missing.css"}}{{end}}`)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := gohtmx.NewPage()
+			p.Static("/static/", fstest.MapFS{})
+			p.Add(test.component)
+			require.ErrorContains(t, p.Validate()["/"], "failed to read asset 'missing.css'")
+			_, err := p.Build()
+			require.ErrorContains(t, err, "failed to read asset 'missing.css'")
+		})
+	}
+}
+
+func TestHTMXAssetsEmbedded(t *testing.T) {
+	a := gohtmx.NewHTMXAssets(gohtmx.DefaultHTMXPath)
+	for _, name := range []string{"htmx.min.js", "ext/sse.js", "ext/ws.js", "ext/head-support.js"} {
+		url, err := a.URL(name)
+		require.NoError(t, err, "%s is not vendored, run go generate", name)
+		w := httptest.NewRecorder()
+		a.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+		require.Equal(t, http.StatusOK, w.Code, name)
+		require.NotEmpty(t, w.Body.String(), name)
+	}
+}
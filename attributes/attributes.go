@@ -14,6 +14,16 @@ func New() *Attributes {
 // Attributes are rendered in alphabetical order. Attributes are stored as Elements to allow for templating.
 type Attributes struct {
 	Values map[string][]string
+	// Conditions are attributes only rendered when their template condition is true. They are written after the Values
+	// in the order they were added.
+	Conditions []Condition
+}
+
+// Condition is an attribute rendered only when the template pipeline of the Condition is true.
+type Condition struct {
+	Pipeline string
+	Name     string
+	Value    string
 }
 
 // Get returns the first value of the attribute if it exists.
@@ -74,6 +84,14 @@ func (a *Attributes) Write(w io.Writer) error {
 		}
 		write(`"`)
 	}
+	// Conditions carry their own separator, so nothing is left behind when they are not rendered.
+	for _, c := range a.Conditions {
+		write(`{{if ` + c.Pipeline + `}} ` + c.Name)
+		if c.Value != "" {
+			write(`="` + c.Value + `"`)
+		}
+		write(`{{end}}`)
+	}
 	return err
 }
 
@@ -84,12 +102,12 @@ func (a *Attributes) Copy() *Attributes {
 	for key, value := range a.Values {
 		n[key] = value
 	}
-	return &Attributes{Values: n}
+	return &Attributes{Values: n, Conditions: append([]Condition{}, a.Conditions...)}
 }
 
 // IsEmpty returns true if there are no attributes.
 func (a *Attributes) IsEmpty() bool {
-	return a == nil || a.Values == nil || len(a.Values) == 0
+	return a == nil || len(a.Values) == 0 && len(a.Conditions) == 0
 }
 
 func (a *Attributes) Delete(name string) *Attributes {
@@ -124,3 +142,11 @@ func (a *Attributes) Bool(name string, active bool) *Attributes {
 	}
 	return a
 }
+
+// If adds a named value to the attributes that is only rendered when the template pipeline is true, such as
+// "$hx.Socket". An empty value adds a flag.
+func (a *Attributes) If(pipeline string, name string, value string) *Attributes {
+	a = a.Ensure()
+	a.Conditions = append(a.Conditions, Condition{Pipeline: pipeline, Name: name, Value: value})
+	return a
+}
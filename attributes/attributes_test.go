@@ -93,6 +93,16 @@ func TestAttributes_Write(t *testing.T) {
 			attrs:    attributes.New().String("keyA", "A").String("keyB", "B").Strings("keyC", "C", "D").Bool("keyD", true),
 			expected: `keyA="A" keyB="B" keyC="C D" keyD`,
 		},
+		{
+			desc:     "conditional attributes",
+			attrs:    attributes.New().If(".a", "keyB", "B").String("keyA", "A").If(".c", "keyC", ""),
+			expected: `keyA="A"{{if .a}} keyB="B"{{end}}{{if .c}} keyC{{end}}`,
+		},
+		{
+			desc:     "only conditional attributes",
+			attrs:    attributes.New().If(".a", "key", "{{.a}}"),
+			expected: `{{if .a}} key="{{.a}}"{{end}}`,
+		},
 	}
 
 	for _, tC := range testCases {
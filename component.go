@@ -1,7 +1,10 @@
 package gohtmx
 
 import (
+	"encoding/json"
 	"fmt"
+	"html/template"
+	"net/http"
 
 	"github.com/TheWozard/gohtmx/attributes"
 	"github.com/TheWozard/gohtmx/element"
@@ -62,22 +65,74 @@ func (c Tag) Init(p *Page) (element.Element, error) {
 
 // Document the baseline component of an HTML document.
 type Document struct {
-	// Header defines Component to be rendered in between the <head> tags.
+	// Header defines Component to be rendered in between the <head> tags. Components anywhere in the Document can also
+	// add to the head through Title, Meta, Link and Canonical.
 	Header Component
 	// Body defines the Component to be rendered in between the <body> tags.
 	Body Component
+	// Lang is the language of the Document, such as "en".
+	Lang string
+	// CSRF sends the CSRF token with every htmx request through hx-headers. Requires the CSRF Middleware.
+	CSRF bool
+	// Config sets the htmx configuration through the htmx-config meta tag. See https://htmx.org/reference/#config
+	Config map[string]any
+	// Nonce sets the inlineScriptNonce and inlineStyleNonce configuration to the CSP nonce of the request, so content
+	// htmx adds inline is allowed by the policy. Requires the CSP Middleware.
+	Nonce bool
+	// HTMX includes the embedded htmx served by the Page, along with the named Extensions.
+	HTMX       bool
+	Extensions []string
 }
 
 func (d Document) Init(p *Page) (element.Element, error) {
+	body := attributes.New()
+	if d.CSRF {
+		body.String("hx-headers", "{{csrfHeaders $r}}")
+	}
+	head := element.Fragment{}
+	if len(d.Config) > 0 || d.Nonce {
+		id := p.Generator.NewID("config")
+		p.funcs(template.FuncMap{id: d.config})
+		head = append(head, &element.Tag{
+			Name:       "meta",
+			Attributes: attributes.New().String("name", "htmx-config").String("content", fmt.Sprintf("{{%s $r}}", id)),
+		})
+	}
+	extensions := d.Extensions
+	if p.HeadSupport {
+		body.String("hx-ext", "head-support")
+		extensions = append(extensions[:len(extensions):len(extensions)], "head-support")
+	}
+	if d.HTMX {
+		head = append(head, p.Init(HTMXScripts{Extensions: extensions}))
+	}
+	p.modify(p.Path(), func(request *Request) {
+		request.Document = true
+	})
+	head = append(head, headElement{page: p, path: p.Path()})
 	return element.Fragment{
 		element.Raw("<!DOCTYPE html>"),
-		&element.Tag{Name: "html", Content: element.Fragment{
-			&element.Tag{Name: "head", Content: p.Init(d.Header)},
-			&element.Tag{Name: "body", Content: p.Init(d.Body)},
+		&element.Tag{Name: "html", Attributes: attributes.New().String("lang", d.Lang), Content: element.Fragment{
+			&element.Tag{Name: "head", Content: append(head, p.Init(d.Header))},
+			&element.Tag{Name: "body", Attributes: body, Content: p.Init(d.Body)},
 		}},
 	}, nil
 }
 
+// config returns the htmx configuration of the request as JSON.
+func (d Document) config(r *http.Request) (string, error) {
+	config := make(map[string]any, len(d.Config)+2)
+	for key, value := range d.Config {
+		config[key] = value
+	}
+	if d.Nonce {
+		config["inlineScriptNonce"] = CSPNonce(r)
+		config["inlineStyleNonce"] = CSPNonce(r)
+	}
+	raw, err := json.Marshal(config)
+	return string(raw), err
+}
+
 // Div is a shorthand for a "div" Tag.
 type Div struct {
 	ID      string
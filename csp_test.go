@@ -0,0 +1,43 @@
+package gohtmx_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/TheWozard/gohtmx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSP(t *testing.T) {
+	p := gohtmx.NewPage()
+	p.UseAll(gohtmx.CSP(""))
+	p.Add(gohtmx.Document{
+		Nonce:  true,
+		Config: map[string]any{"defaultSwapStyle": "outerHTML"},
+		Header: gohtmx.Fragment{
+			gohtmx.Script{Src: "/htmx.js"},
+			gohtmx.Style{Content: "body { margin: 0; }"},
+		},
+	})
+	h, err := p.Build()
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	policy := w.Header().Get("Content-Security-Policy")
+	start := strings.Index(policy, "'nonce-") + len("'nonce-")
+	nonce := policy[start : start+strings.Index(policy[start:], "'")]
+	require.Equal(t, strings.ReplaceAll(gohtmx.DefaultCSPPolicy, "{nonce}", nonce), policy)
+	require.Equal(t, `<!DOCTYPE html><html><head>`+
+		`<meta content="{&#34;defaultSwapStyle&#34;:&#34;outerHTML&#34;,`+
+		`&#34;inlineScriptNonce&#34;:&#34;`+nonce+`&#34;,&#34;inlineStyleNonce&#34;:&#34;`+nonce+`&#34;}" name="htmx-config"></meta>`+
+		`<script src="/htmx.js" nonce="`+nonce+`"></script>`+
+		`<style nonce="`+nonce+`">body { margin: 0; }</style>`+
+		`</head><body></body></html>`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.NotEqual(t, policy, w.Header().Get("Content-Security-Policy"))
+}
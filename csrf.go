@@ -0,0 +1,116 @@
+package gohtmx
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/TheWozard/gohtmx/element"
+)
+
+const (
+	// CSRFHeader is the request header the CSRF token is read from.
+	CSRFHeader = "X-CSRF-Token"
+	// CSRFField is the form field the CSRF token is read from if the header is not set.
+	CSRFField = "csrf_token"
+)
+
+// ErrCSRF is the error rendered when a request fails CSRF validation.
+var ErrCSRF = errors.New("invalid csrf token")
+
+// CSRFOptions configures the CSRF Middleware.
+type CSRFOptions struct {
+	// CookieName is the name of the cookie holding the token. Defaults to "gohtmx_csrf".
+	CookieName string
+	// Secure restricts the cookie to https.
+	Secure bool
+}
+
+type csrfKey struct{}
+
+// CSRF creates a Middleware that protects requests from cross site request forgery using a double submit cookie.
+// Each client is issued a token in a cookie, and every request with an unsafe method must send the same token in the
+// CSRFHeader or CSRFField. Requests without a matching token are rendered as a forbidden error through HandleError.
+// Interactions are only protected when they are beneath the path it is added at through UseAll. Set Document.CSRF so
+// htmx sends the token with every request.
+func CSRF(options CSRFOptions) Middleware {
+	if options.CookieName == "" {
+		options.CookieName = "gohtmx_csrf"
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// The token was already checked by an outer CSRF.
+			if CSRFToken(r) != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			token := ""
+			if cookie, err := r.Cookie(options.CookieName); err == nil && cookie.Value != "" {
+				token = cookie.Value
+			}
+			if !csrfSafeMethod(r.Method) {
+				sent := r.Header.Get(CSRFHeader)
+				if sent == "" {
+					sent = r.FormValue(CSRFField)
+				}
+				if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(sent)) != 1 {
+					HandleError(w, r, NewStatusError(http.StatusForbidden, ErrCSRF))
+					return
+				}
+			}
+			if token == "" {
+				raw := make([]byte, 32)
+				_, err := rand.Read(raw)
+				if err != nil {
+					HandleError(w, r, err)
+					return
+				}
+				token = base64.RawURLEncoding.EncodeToString(raw)
+				http.SetCookie(w, &http.Cookie{
+					Name:     options.CookieName,
+					Value:    token,
+					Path:     "/",
+					HttpOnly: true,
+					Secure:   options.Secure,
+					SameSite: http.SameSiteLaxMode,
+				})
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), csrfKey{}, token)))
+		})
+	}
+}
+
+func csrfSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+// CSRFToken returns the CSRF token of the request, or an empty string if the request is not wrapped by CSRF.
+func CSRFToken(r *http.Request) string {
+	if r == nil {
+		return ""
+	}
+	token, _ := r.Context().Value(csrfKey{}).(string)
+	return token
+}
+
+// csrfHeaders returns the CSRF token of the request formatted for hx-headers.
+func csrfHeaders(r *http.Request) (string, error) {
+	raw, err := json.Marshal(map[string]string{CSRFHeader: CSRFToken(r)})
+	return string(raw), err
+}
+
+// CSRFInput is a hidden input containing the CSRF token. This is needed for forms that are not submitted by htmx,
+// including forms sent over a Socket.
+type CSRFInput struct{}
+
+func (CSRFInput) Init(p *Page) (element.Element, error) {
+	return p.Init(Input{Type: "hidden", Name: CSRFField, Value: "{{csrf $r}}"}), nil
+}
@@ -0,0 +1,87 @@
+package gohtmx_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/TheWozard/gohtmx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSRF(t *testing.T) {
+	p := gohtmx.NewPage()
+	p.ErrorComponent = gohtmx.Raw(`{{.status}}`)
+	p.UseAll(gohtmx.CSRF(gohtmx.CSRFOptions{}))
+	interaction := gohtmx.NewInteraction("submit")
+	p.Add(gohtmx.Document{
+		CSRF: true,
+		Body: gohtmx.Fragment{
+			interaction,
+			interaction.Swap().Update(gohtmx.Div{Content: gohtmx.Raw("submitted")}),
+			interaction.Trigger().Target(gohtmx.Button{}),
+		},
+	})
+	h, err := p.Build()
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	token := cookies[0].Value
+	require.Contains(t, w.Body.String(), `<body hx-headers="{&#34;X-CSRF-Token&#34;:&#34;`+token+`&#34;}">`)
+
+	testCases := []struct {
+		desc     string
+		setup    func(r *http.Request)
+		body     string
+		code     int
+		expected string
+	}{
+		{
+			desc:     "missing token",
+			setup:    func(r *http.Request) {},
+			code:     http.StatusForbidden,
+			expected: "403",
+		},
+		{
+			desc: "mismatched token",
+			setup: func(r *http.Request) {
+				r.Header.Set(gohtmx.CSRFHeader, "other")
+			},
+			code:     http.StatusForbidden,
+			expected: "403",
+		},
+		{
+			desc: "header token",
+			setup: func(r *http.Request) {
+				r.Header.Set(gohtmx.CSRFHeader, token)
+			},
+			code:     http.StatusOK,
+			expected: `<div id="gohtmx_0">submitted</div>`,
+		},
+		{
+			desc:     "form token",
+			setup:    func(r *http.Request) {},
+			body:     url.Values{gohtmx.CSRFField: {token}}.Encode(),
+			code:     http.StatusOK,
+			expected: `<div id="gohtmx_0">submitted</div>`,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/submit", strings.NewReader(tC.body))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			r.Header.Set("HX-Request", "true")
+			r.AddCookie(cookies[0])
+			tC.setup(r)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			require.Equal(t, tC.code, w.Code)
+			require.Equal(t, tC.expected, w.Body.String())
+		})
+	}
+}
@@ -1,9 +1,6 @@
 package gohtmx
 
-import (
-	"net/http"
-	"net/url"
-)
+import "net/http"
 
 // GetDataFromRequest returns Data with the values from the request. Handles both GET and POST requests.
 func GetDataFromRequest(params ...string) func(r *http.Request) Data {
@@ -74,7 +71,7 @@ func (d Data) Merge(a Data) Data {
 
 // SetValuesInResponse sets the data Data in the response. Handles both GET and POST requests.
 func (d Data) SetInResponse(w http.ResponseWriter, r *http.Request) {
-	current, err := url.Parse(r.Header.Get("HX-Current-URL"))
+	current, err := NewHXRequest(r).URL()
 	if err != nil {
 		return
 	}
@@ -83,7 +80,7 @@ func (d Data) SetInResponse(w http.ResponseWriter, r *http.Request) {
 		query.Set(key, value.(string))
 	}
 	current.RawQuery = query.Encode()
-	w.Header().Set("HX-Push-Url", current.String())
+	NewResponse(w).PushURL(current.String())
 }
 
 // Subset creates a new Data map with only the passed keys.
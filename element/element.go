@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/TheWozard/gohtmx/attributes"
 )
@@ -14,6 +15,8 @@ type Element interface {
 	Render(w io.Writer) error
 	Validate() error
 	GetTags() []*Tag
+	// Children returns the Elements directly contained by the Element.
+	Children() []Element
 }
 
 // Fragment defines a slice of Fragment that can be used as a single Element.
@@ -41,6 +44,16 @@ func (f Fragment) Validate() error {
 	return errors.Join(errs...)
 }
 
+func (f Fragment) Children() []Element {
+	children := make([]Element, 0, len(f))
+	for _, element := range f {
+		if element != nil {
+			children = append(children, element)
+		}
+	}
+	return children
+}
+
 func (f Fragment) GetTags() []*Tag {
 	children := make([]*Tag, 0, len(f))
 	for _, element := range f {
@@ -63,6 +76,10 @@ func (r Raw) Validate() error {
 	return nil
 }
 
+func (r Raw) Children() []Element {
+	return nil
+}
+
 func (r Raw) GetTags() []*Tag {
 	return []*Tag{}
 }
@@ -80,6 +97,10 @@ func (r RawError) Validate() error {
 	return r.Err
 }
 
+func (r RawError) Children() []Element {
+	return nil
+}
+
 func (r RawError) GetTags() []*Tag {
 	return []*Tag{}
 }
@@ -100,9 +121,12 @@ func (t *Tag) Render(w io.Writer) error {
 		return ErrPrependPath(fmt.Errorf(`failed to write start tag start: %w`, err), t.Name)
 	}
 	if !t.Attributes.IsEmpty() {
-		_, err = w.Write([]byte(` `))
-		if err != nil {
-			return ErrPrependPath(fmt.Errorf(`failed to write start tag attribute separator: %w`, err), t.Name)
+		// Conditions write their own separator.
+		if len(t.Attributes.Values) > 0 {
+			_, err = w.Write([]byte(` `))
+			if err != nil {
+				return ErrPrependPath(fmt.Errorf(`failed to write start tag attribute separator: %w`, err), t.Name)
+			}
 		}
 		err = t.Attributes.Write(w)
 		if err != nil {
@@ -136,6 +160,22 @@ func (t *Tag) Validate() error {
 	return nil
 }
 
+func (t *Tag) Children() []Element {
+	if t.Content == nil {
+		return nil
+	}
+	return []Element{t.Content}
+}
+
 func (t *Tag) GetTags() []*Tag {
 	return []*Tag{t}
 }
+
+// Attr returns the value of the attribute as written, and whether the attribute is present.
+func (t *Tag) Attr(name string) (string, bool) {
+	if t.Attributes == nil {
+		return "", false
+	}
+	values, ok := t.Attributes.Values[name]
+	return strings.Join(values, " "), ok
+}
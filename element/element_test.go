@@ -79,6 +79,15 @@ func TestElements(t *testing.T) {
 			expected: `<div class="test" id="test"></div>`,
 			tags:     []*element.Tag{{Name: "div", Attributes: attributes.New().String("id", "test").Strings("class", "test")}},
 		},
+		{
+			desc: "tag with conditional attributes",
+			element: &element.Tag{
+				Name:       "div",
+				Attributes: attributes.New().If(".a", "key", "value"),
+			},
+			expected: `<div{{if .a}} key="value"{{end}}></div>`,
+			tags:     []*element.Tag{{Name: "div", Attributes: attributes.New().If(".a", "key", "value")}},
+		},
 		{
 			desc: "tag with content",
 			element: &element.Tag{
@@ -99,3 +108,16 @@ func TestElements(t *testing.T) {
 		})
 	}
 }
+
+func TestTagAttr(t *testing.T) {
+	tag := &element.Tag{Name: "div", Attributes: attributes.New().String("class", "a").String("class", "b").Bool("hidden", true)}
+	value, ok := tag.Attr("class")
+	require.True(t, ok)
+	require.Equal(t, "a b", value)
+	_, ok = tag.Attr("hidden")
+	require.True(t, ok)
+	_, ok = tag.Attr("id")
+	require.False(t, ok)
+	_, ok = (&element.Tag{Name: "div"}).Attr("id")
+	require.False(t, ok)
+}
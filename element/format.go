@@ -0,0 +1,251 @@
+package element
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// RenderMode controls the whitespace of rendered output.
+type RenderMode int
+
+const (
+	// RenderDefault renders Elements as they are, without adding or removing whitespace.
+	RenderDefault RenderMode = iota
+	// RenderPretty places block level tags on their own lines, indented by their depth.
+	RenderPretty
+	// RenderMinified removes whitespace between block level tags and collapses all other whitespace.
+	RenderMinified
+)
+
+// RenderOptions configures RenderWith. Whitespace is only added or removed where it does not change how the document
+// is displayed: next to block level tags, and never within inline content or tags that preserve whitespace, such as
+// <pre> and <textarea>. This assumes tags keep their default display. Template actions are left as they are.
+type RenderOptions struct {
+	Mode RenderMode
+	// Indent is the indentation of each level in RenderPretty. Defaults to two spaces.
+	Indent string
+}
+
+// RenderWith renders the Element to the io.Writer, formatted by the RenderOptions.
+func RenderWith(e Element, w io.Writer, options RenderOptions) error {
+	if options.Mode == RenderDefault {
+		return e.Render(w)
+	}
+	raw := bytes.NewBuffer(nil)
+	err := e.Render(raw)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(Format(raw.Bytes(), options))
+	return err
+}
+
+// formatBlockTags are the tags whitespace next to which is not displayed.
+var formatBlockTags = tagSet("address", "article", "aside", "blockquote", "body", "caption", "col", "colgroup", "dd",
+	"details", "dialog", "div", "dl", "dt", "fieldset", "figcaption", "figure", "footer", "form", "h1", "h2", "h3",
+	"h4", "h5", "h6", "head", "header", "hgroup", "hr", "html", "li", "link", "main", "menu", "meta", "nav", "ol",
+	"optgroup", "option", "p", "pre", "script", "section", "style", "summary", "table", "tbody", "td", "template",
+	"tfoot", "th", "thead", "title", "tr", "ul", "!doctype")
+
+// preservedTags keep their content exactly as written.
+var preservedTags = tagSet("pre", "textarea", "script", "style", "title")
+
+// rawTextTags contain text through to their end tag, even where the text looks like a tag.
+var rawTextTags = tagSet("script", "style", "textarea", "title")
+
+type tokenKind int
+
+const (
+	tokenText tokenKind = iota
+	tokenAction
+	tokenStart
+	tokenEnd
+	tokenOther
+)
+
+type token struct {
+	kind tokenKind
+	name string
+	raw  string
+}
+
+// block returns if whitespace next to the token is not displayed.
+func (t token) block() bool {
+	return (t.kind == tokenStart || t.kind == tokenEnd || t.kind == tokenOther) && formatBlockTags[t.name]
+}
+
+// Format formats rendered html by the RenderOptions. See RenderOptions.
+func Format(raw []byte, options RenderOptions) []byte {
+	if options.Mode == RenderDefault {
+		return raw
+	}
+	indent := options.Indent
+	if indent == "" {
+		indent = "  "
+	}
+	tokens := tokenize(string(raw))
+	out := bytes.NewBuffer(nil)
+	depth := 0
+	var previous *token
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		if t.kind == tokenText && strings.TrimSpace(t.raw) == "" {
+			// Whitespace between block level tags is not displayed.
+			next := i + 1
+			if (previous == nil || previous.block()) && (next == len(tokens) || tokens[next].block()) {
+				continue
+			}
+		}
+		if t.kind == tokenEnd && !voidTags[t.name] {
+			depth--
+		}
+		empty := previous != nil && previous.kind == tokenStart && t.kind == tokenEnd && previous.name == t.name
+		if options.Mode == RenderPretty && previous != nil && previous.block() && t.block() && !empty {
+			out.WriteString("\n" + strings.Repeat(indent, max(depth, 0)))
+		}
+		switch {
+		case t.kind == tokenText && options.Mode == RenderMinified:
+			out.WriteString(collapseSpace(t.raw))
+		default:
+			out.WriteString(t.raw)
+		}
+		previous = &tokens[i]
+		if t.kind == tokenStart && !voidTags[t.name] {
+			depth++
+			if preservedTags[t.name] {
+				// The content is written exactly, through to the end tag.
+				for i+1 < len(tokens) {
+					i++
+					out.WriteString(tokens[i].raw)
+					if tokens[i].kind == tokenEnd && tokens[i].name == t.name {
+						depth--
+						previous = &tokens[i]
+						break
+					}
+				}
+			}
+		}
+	}
+	return out.Bytes()
+}
+
+// collapseSpace replaces each run of whitespace with a single space.
+func collapseSpace(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range s {
+		if r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '\f' {
+			if !space {
+				b.WriteByte(' ')
+			}
+			space = true
+			continue
+		}
+		space = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// tokenize splits rendered html into tags, template actions and text.
+func tokenize(s string) []token {
+	tokens := []token{}
+	for len(s) > 0 {
+		switch {
+		case strings.HasPrefix(s, "{{"):
+			end := strings.Index(s, "}}")
+			if end < 0 {
+				end = len(s) - 2
+			}
+			tokens = append(tokens, token{kind: tokenAction, raw: s[:end+2]})
+			s = s[end+2:]
+		case strings.HasPrefix(s, "<!--"):
+			end := strings.Index(s, "-->")
+			if end < 0 {
+				end = len(s) - 3
+			}
+			tokens = append(tokens, token{kind: tokenText, raw: s[:end+3]})
+			s = s[end+3:]
+		case len(s) > 1 && s[0] == '<' && (s[1] == '/' || s[1] == '!' || isLetter(s[1])):
+			end := tagEnd(s)
+			raw := s[:end]
+			s = s[end:]
+			t := token{kind: tokenStart, raw: raw}
+			name := raw[1:]
+			if strings.HasPrefix(name, "/") {
+				t.kind = tokenEnd
+				name = name[1:]
+			} else if strings.HasPrefix(name, "!") {
+				t.kind = tokenOther
+			}
+			fields := strings.FieldsFunc(name, func(r rune) bool {
+				return r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '>' || r == '/'
+			})
+			if len(fields) > 0 {
+				t.name = strings.ToLower(fields[0])
+			}
+			tokens = append(tokens, t)
+			if t.kind == tokenStart && rawTextTags[t.name] {
+				end := rawTextEnd(s, t.name)
+				if end > 0 {
+					tokens = append(tokens, token{kind: tokenText, raw: s[:end]})
+				}
+				s = s[end:]
+			}
+		default:
+			end := 1
+			for end < len(s) && s[end] != '<' && !strings.HasPrefix(s[end:], "{{") {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenText, raw: s[:end]})
+			s = s[end:]
+		}
+	}
+	return tokens
+}
+
+// tagEnd returns the index after the end of the tag at the start of s, skipping quoted values and template actions.
+func tagEnd(s string) int {
+	var quote byte
+	for i := 1; i < len(s); i++ {
+		switch {
+		case strings.HasPrefix(s[i:], "{{"):
+			end := strings.Index(s[i:], "}}")
+			if end < 0 {
+				return len(s)
+			}
+			i += end + 1
+		case quote != 0:
+			if s[i] == quote {
+				quote = 0
+			}
+		case s[i] == '"' || s[i] == '\'':
+			quote = s[i]
+		case s[i] == '>':
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+// rawTextEnd returns the index of the end tag with the name in s, or the length of s if it is not closed.
+func rawTextEnd(s string, name string) int {
+	lower := strings.ToLower(s)
+	for i := 0; ; {
+		j := strings.Index(lower[i:], "</"+name)
+		if j < 0 {
+			return len(s)
+		}
+		i += j
+		// The name must end for this to be the end tag, so </scripts> does not close <script>.
+		if k := i + 2 + len(name); k == len(s) || !isLetter(s[k]) && s[k] != '-' {
+			return i
+		}
+		i += 2
+	}
+}
+
+func isLetter(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
@@ -0,0 +1,89 @@
+package element_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/TheWozard/gohtmx/attributes"
+	"github.com/TheWozard/gohtmx/element"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderWith(t *testing.T) {
+	tag := func(name string, content ...element.Element) *element.Tag {
+		return &element.Tag{Name: name, Content: element.Fragment(content)}
+	}
+	testCases := []struct {
+		desc     string
+		element  element.Element
+		pretty   string
+		minified string
+	}{
+		{
+			desc:     "block tags",
+			element:  tag("div", tag("ul", tag("li", element.Raw("a")), tag("li", element.Raw("b")))),
+			pretty:   "<div>\n  <ul>\n    <li>a</li>\n    <li>b</li>\n  </ul>\n</div>",
+			minified: "<div><ul><li>a</li><li>b</li></ul></div>",
+		},
+		{
+			desc:     "inline content",
+			element:  tag("p", element.Raw("Hello  "), tag("b", element.Raw("big")), element.Raw("\n world")),
+			pretty:   "<p>Hello  <b>big</b>\n world</p>",
+			minified: "<p>Hello <b>big</b> world</p>",
+		},
+		{
+			desc:     "whitespace between block tags",
+			element:  tag("div", element.Raw("\n  "), tag("p", element.Raw("a")), element.Raw("\n")),
+			pretty:   "<div>\n  <p>a</p>\n</div>",
+			minified: "<div><p>a</p></div>",
+		},
+		{
+			desc:     "preformatted",
+			element:  tag("div", tag("pre", element.Raw("  a\n"), tag("div", element.Raw(" b ")), element.Raw("\n")), tag("textarea", element.Raw(" c  d "))),
+			pretty:   "<div>\n  <pre>  a\n<div> b </div>\n</pre><textarea> c  d </textarea></div>",
+			minified: "<div><pre>  a\n<div> b </div>\n</pre><textarea> c  d </textarea></div>",
+		},
+		{
+			desc:     "inline textarea",
+			element:  tag("div", tag("textarea", element.Raw("a")), element.Raw(" "), tag("textarea", element.Raw("b"))),
+			pretty:   "<div><textarea>a</textarea> <textarea>b</textarea></div>",
+			minified: "<div><textarea>a</textarea> <textarea>b</textarea></div>",
+		},
+		{
+			desc:     "raw text",
+			element:  tag("div", tag("script", element.Raw(`if (a<b) { x = 'it'; }`)), tag("p")),
+			pretty:   "<div>\n  <script>if (a<b) { x = 'it'; }</script>\n  <p></p>\n</div>",
+			minified: "<div><script>if (a<b) { x = 'it'; }</script><p></p></div>",
+		},
+		{
+			desc:     "raw text with markup",
+			element:  tag("div", tag("style", element.Raw(`a > b { content: "</p>"; }`)), tag("textarea", element.Raw(`<b>  x`))),
+			pretty:   "<div>\n  <style>a > b { content: \"</p>\"; }</style><textarea><b>  x</textarea></div>",
+			minified: "<div><style>a > b { content: \"</p>\"; }</style><textarea><b>  x</textarea></div>",
+		},
+		{
+			desc: "attributes and template actions",
+			element: element.Fragment{
+				element.Raw(`{{if gt .n 1}}`),
+				&element.Tag{Name: "div", Attributes: attributes.New().String("title", "a > b").String("class", `{{if .x}}x{{end}}`), Content: tag("p")},
+				element.Raw(`{{end}}`),
+			},
+			pretty:   "{{if gt .n 1}}<div class=\"{{if .x}}x{{end}}\" title=\"a > b\">\n  <p></p>\n</div>{{end}}",
+			minified: "{{if gt .n 1}}<div class=\"{{if .x}}x{{end}}\" title=\"a > b\"><p></p></div>{{end}}",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			pretty := bytes.NewBuffer(nil)
+			require.Nil(t, element.RenderWith(tC.element, pretty, element.RenderOptions{Mode: element.RenderPretty}))
+			require.Equal(t, tC.pretty, pretty.String())
+
+			minified := bytes.NewBuffer(nil)
+			require.Nil(t, element.RenderWith(tC.element, minified, element.RenderOptions{Mode: element.RenderMinified}))
+			require.Equal(t, tC.minified, minified.String())
+
+			// Both modes only differ in whitespace that is not displayed.
+			require.Equal(t, tC.minified, string(element.Format(pretty.Bytes(), element.RenderOptions{Mode: element.RenderMinified})))
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package element
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrContentModel is reported by ValidateHTML for every Tag that breaks the HTML content model.
+var ErrContentModel = errors.New("invalid html")
+
+// voidTags cannot have any content.
+var voidTags = tagSet("area", "base", "br", "col", "embed", "hr", "img", "input", "link", "meta", "source", "track", "wbr")
+
+// blockTags cannot be within a <p>, as the parser implicitly closes the <p> before them.
+var blockTags = tagSet("address", "article", "aside", "blockquote", "details", "dialog", "div", "dl", "fieldset",
+	"figcaption", "figure", "footer", "form", "h1", "h2", "h3", "h4", "h5", "h6", "header", "hgroup", "hr", "main",
+	"menu", "nav", "ol", "p", "pre", "section", "table", "ul")
+
+// interactiveTags cannot be within an <a> or <button>.
+var interactiveTags = tagSet("a", "button", "details", "embed", "iframe", "label", "select", "textarea")
+
+// parentTags are the Tags each Tag must be directly within. Tags without a parent Tag are not checked, as they can be
+// swapped into any parent.
+var parentTags = map[string]map[string]bool{
+	"li":       tagSet("ul", "ol", "menu"),
+	"dt":       tagSet("dl", "div"),
+	"dd":       tagSet("dl", "div"),
+	"tr":       tagSet("table", "thead", "tbody", "tfoot"),
+	"td":       tagSet("tr"),
+	"th":       tagSet("tr"),
+	"thead":    tagSet("table"),
+	"tbody":    tagSet("table"),
+	"tfoot":    tagSet("table"),
+	"caption":  tagSet("table"),
+	"colgroup": tagSet("table"),
+	"col":      tagSet("colgroup", "table"),
+	"option":   tagSet("select", "datalist", "optgroup"),
+	"optgroup": tagSet("select"),
+	"summary":  tagSet("details"),
+	"legend":   tagSet("fieldset"),
+}
+
+func tagSet(names ...string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	return set
+}
+
+// ValidateHTML checks the tree of the Element against the HTML content model. This catches markup browsers silently
+// restructure, such as a <div> within a <p>, an <li> outside of a list, interactive content within an <a> or
+// <button>, table rows outside of a table, void elements with content and multiple <main> elements. Every violation is
+// reported as a PathError.
+func ValidateHTML(e Element) error {
+	errs := []error{}
+	report := func(path []string, format string, args ...any) {
+		err := fmt.Errorf("%w: %s", ErrContentModel, fmt.Sprintf(format, args...))
+		errs = append(errs, PathError{Path: path, Err: err})
+	}
+	ancestors := []*Tag{}
+	mains := 0
+	_ = Walk(e, func(e Element, path []string) error {
+		t, ok := e.(*Tag)
+		if !ok {
+			return nil
+		}
+		name := strings.ToLower(t.Name)
+		if allowed, ok := parentTags[name]; ok && len(ancestors) > 0 {
+			parent := strings.ToLower(ancestors[len(ancestors)-1].Name)
+			if !allowed[parent] {
+				report(path, "<%s> cannot be within <%s>", name, parent)
+			}
+		}
+		for i := len(ancestors) - 1; i >= 0; i-- {
+			ancestor := strings.ToLower(ancestors[i].Name)
+			if ancestor == "p" && blockTags[name] {
+				report(path, "<%s> cannot be within <p>", name)
+				break
+			}
+			if (ancestor == "a" || ancestor == "button") && interactive(t) {
+				report(path, "<%s> cannot be within <%s>", name, ancestor)
+				break
+			}
+			if ancestor == "form" && name == "form" {
+				report(path, "<form> cannot be within <form>")
+				break
+			}
+		}
+		if voidTags[name] && t.Content != nil {
+			content := bytes.NewBuffer(nil)
+			if err := t.Content.Render(content); err == nil && content.Len() > 0 {
+				report(path, "<%s> cannot have content", name)
+			}
+		}
+		if name == "main" {
+			if _, hidden := t.Attr("hidden"); !hidden {
+				mains++
+				if mains > 1 {
+					report(path, "only one visible <main> is allowed")
+				}
+			}
+		}
+		ancestors = append(ancestors, t)
+		return nil
+	}, func(e Element, path []string) error {
+		if _, ok := e.(*Tag); ok {
+			ancestors = ancestors[:len(ancestors)-1]
+		}
+		return nil
+	})
+	return errors.Join(errs...)
+}
+
+// interactive returns if the Tag is interactive content.
+func interactive(t *Tag) bool {
+	name := strings.ToLower(t.Name)
+	if interactiveTags[name] {
+		return true
+	}
+	switch name {
+	case "input":
+		kind, _ := t.Attr("type")
+		return kind != "hidden"
+	case "audio", "video":
+		_, controls := t.Attr("controls")
+		return controls
+	case "img":
+		_, usemap := t.Attr("usemap")
+		return usemap
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package element_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/TheWozard/gohtmx/attributes"
+	"github.com/TheWozard/gohtmx/element"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateHTML(t *testing.T) {
+	tag := func(name string, content ...element.Element) *element.Tag {
+		return &element.Tag{Name: name, Content: element.Fragment(content)}
+	}
+	testCases := []struct {
+		desc     string
+		element  element.Element
+		expected []string
+	}{
+		{
+			desc:    "valid",
+			element: tag("main", tag("p", tag("a", element.Raw("link"))), tag("ul", tag("li"), tag("li"))),
+		},
+		{
+			desc:    "root tags can be swapped anywhere",
+			element: element.Fragment{tag("li"), tag("tr", tag("td"))},
+		},
+		{
+			desc:     "block in paragraph",
+			element:  tag("p", tag("span", tag("div"))),
+			expected: []string{"p(0).span(0).div(0) invalid html: <div> cannot be within <p>"},
+		},
+		{
+			desc:     "list item outside of list",
+			element:  tag("div", tag("li")),
+			expected: []string{"div(0).li(0) invalid html: <li> cannot be within <div>"},
+		},
+		{
+			desc:     "interactive in button",
+			element:  tag("button", tag("span", tag("a"))),
+			expected: []string{"button(0).span(0).a(0) invalid html: <a> cannot be within <button>"},
+		},
+		{
+			desc: "hidden input in link",
+			element: tag("a", &element.Tag{
+				Name:       "input",
+				Attributes: attributes.New().String("type", "hidden"),
+			}),
+		},
+		{
+			desc:     "row outside of table",
+			element:  tag("div", tag("tr", tag("td"))),
+			expected: []string{"div(0).tr(0) invalid html: <tr> cannot be within <div>"},
+		},
+		{
+			desc:     "nested form",
+			element:  tag("form", tag("div", tag("form"))),
+			expected: []string{"form(0).div(0).form(0) invalid html: <form> cannot be within <form>"},
+		},
+		{
+			desc:     "void with content",
+			element:  tag("br", element.Raw("text")),
+			expected: []string{"br(0) invalid html: <br> cannot have content"},
+		},
+		{
+			desc: "multiple main",
+			element: element.Fragment{
+				tag("main"),
+				&element.Tag{Name: "main", Attributes: attributes.New().Bool("hidden", true)},
+				tag("main"),
+			},
+			expected: []string{"main(2) invalid html: only one visible <main> is allowed"},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			err := element.ValidateHTML(tC.element)
+			if len(tC.expected) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, element.ErrContentModel)
+			messages := []string{}
+			for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+				var pe element.PathError
+				require.True(t, errors.As(e, &pe))
+				messages = append(messages, e.Error())
+			}
+			require.Equal(t, tC.expected, messages)
+		})
+	}
+}
@@ -15,6 +15,10 @@ func (v OnValidate) Validate() error {
 	return v()
 }
 
+func (v OnValidate) Children() []Element {
+	return nil
+}
+
 func (v OnValidate) GetTags() []*Tag {
 	return []*Tag{}
 }
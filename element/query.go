@@ -0,0 +1,353 @@
+package element
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Query returns every Tag in the tree of the Element matching the CSS selector, in document order.
+// See CompileSelector for the supported selectors.
+func Query(e Element, selector string) ([]*Tag, error) {
+	s, err := CompileSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	return s.Query(e), nil
+}
+
+// QueryOne returns the first Tag in the tree of the Element matching the CSS selector, or nil if there is none.
+func QueryOne(e Element, selector string) (*Tag, error) {
+	tags, err := Query(e, selector)
+	if err != nil || len(tags) == 0 {
+		return nil, err
+	}
+	return tags[0], nil
+}
+
+// Selector is a compiled CSS selector that matches Tags.
+type Selector struct {
+	groups [][]compound
+}
+
+// compound is a compound selector along with the combinator relating it to the compound before it.
+type compound struct {
+	combinator byte
+	name       string
+	id         string
+	classes    []string
+	attrs      []attrSelector
+}
+
+type attrSelector struct {
+	name  string
+	op    string
+	value string
+}
+
+// CompileSelector parses a CSS selector. Supported are type, universal, id, class and attribute selectors, including
+// the =, ~=, |=, ^=, $= and *= attribute operators, the descendant, child (>), adjacent sibling (+) and general
+// sibling (~) combinators, and selector lists separated by commas. Pseudo-classes are not supported.
+func CompileSelector(selector string) (*Selector, error) {
+	p := &selectorParser{input: selector}
+	s := &Selector{}
+	for {
+		group, err := p.complex()
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector '%s': %w", selector, err)
+		}
+		s.groups = append(s.groups, group)
+		p.spaces()
+		if p.done() {
+			return s, nil
+		}
+		if p.peek() != ',' {
+			return nil, fmt.Errorf("invalid selector '%s': unexpected '%c' at %d", selector, p.peek(), p.pos)
+		}
+		p.pos++
+	}
+}
+
+// MustCompileSelector is like CompileSelector but panics if the selector is invalid.
+func MustCompileSelector(selector string) *Selector {
+	s, err := CompileSelector(selector)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Query returns every Tag in the tree of the Element matching the Selector, in document order.
+func (s *Selector) Query(e Element) []*Tag {
+	matches := []*Tag{}
+	// Each node tracks the parent and previous sibling of a Tag so combinators can be matched.
+	var parent *node
+	siblings := []*node{nil}
+	_ = Walk(e, func(e Element, _ []string) error {
+		t, ok := e.(*Tag)
+		if !ok {
+			return nil
+		}
+		n := &node{tag: t, parent: parent, prev: siblings[len(siblings)-1]}
+		siblings[len(siblings)-1] = n
+		if s.match(n) {
+			matches = append(matches, t)
+		}
+		parent = n
+		siblings = append(siblings, nil)
+		return nil
+	}, func(e Element, _ []string) error {
+		if _, ok := e.(*Tag); ok {
+			parent = parent.parent
+			siblings = siblings[:len(siblings)-1]
+		}
+		return nil
+	})
+	return matches
+}
+
+type node struct {
+	tag    *Tag
+	parent *node
+	prev   *node
+}
+
+func (s *Selector) match(n *node) bool {
+	for _, group := range s.groups {
+		if matchComplex(group, len(group)-1, n) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchComplex matches the compounds up to i against the node, from right to left.
+func matchComplex(group []compound, i int, n *node) bool {
+	c := group[i]
+	if !c.matches(n.tag) {
+		return false
+	}
+	if i == 0 {
+		return true
+	}
+	switch c.combinator {
+	case '>':
+		return n.parent != nil && matchComplex(group, i-1, n.parent)
+	case '+':
+		return n.prev != nil && matchComplex(group, i-1, n.prev)
+	case '~':
+		for prev := n.prev; prev != nil; prev = prev.prev {
+			if matchComplex(group, i-1, prev) {
+				return true
+			}
+		}
+	default:
+		for parent := n.parent; parent != nil; parent = parent.parent {
+			if matchComplex(group, i-1, parent) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c compound) matches(t *Tag) bool {
+	if c.name != "" && c.name != "*" && !strings.EqualFold(c.name, t.Name) {
+		return false
+	}
+	if c.id != "" {
+		if id, ok := t.Attr("id"); !ok || id != c.id {
+			return false
+		}
+	}
+	if len(c.classes) > 0 {
+		value, _ := t.Attr("class")
+		classes := strings.Fields(value)
+		for _, class := range c.classes {
+			if !contains(classes, class) {
+				return false
+			}
+		}
+	}
+	for _, a := range c.attrs {
+		value, ok := t.Attr(a.name)
+		if !ok {
+			return false
+		}
+		switch a.op {
+		case "=":
+			ok = value == a.value
+		case "~=":
+			ok = contains(strings.Fields(value), a.value)
+		case "|=":
+			ok = value == a.value || strings.HasPrefix(value, a.value+"-")
+		case "^=":
+			ok = a.value != "" && strings.HasPrefix(value, a.value)
+		case "$=":
+			ok = a.value != "" && strings.HasSuffix(value, a.value)
+		case "*=":
+			ok = a.value != "" && strings.Contains(value, a.value)
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+type selectorParser struct {
+	input string
+	pos   int
+}
+
+func (p *selectorParser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *selectorParser) peek() byte {
+	if p.done() {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+// spaces skips whitespace, returning if any was skipped.
+func (p *selectorParser) spaces() bool {
+	start := p.pos
+	for !p.done() && strings.IndexByte(" \t\n\r\f", p.peek()) >= 0 {
+		p.pos++
+	}
+	return p.pos > start
+}
+
+func (p *selectorParser) ident() string {
+	start := p.pos
+	for !p.done() {
+		b := p.peek()
+		if b == '-' || b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= 0x80 {
+			p.pos++
+			continue
+		}
+		break
+	}
+	return p.input[start:p.pos]
+}
+
+func (p *selectorParser) complex() ([]compound, error) {
+	p.spaces()
+	group := []compound{}
+	var combinator byte
+	for {
+		c, err := p.compound()
+		if err != nil {
+			return nil, err
+		}
+		c.combinator = combinator
+		group = append(group, c)
+
+		space := p.spaces()
+		switch b := p.peek(); {
+		case b == '>' || b == '+' || b == '~':
+			combinator = b
+			p.pos++
+			p.spaces()
+		case b == ',' || p.done():
+			return group, nil
+		case space:
+			combinator = ' '
+		default:
+			return nil, fmt.Errorf("unexpected '%c' at %d", b, p.pos)
+		}
+	}
+}
+
+func (p *selectorParser) compound() (compound, error) {
+	c := compound{}
+	if p.peek() == '*' {
+		p.pos++
+		c.name = "*"
+	} else {
+		c.name = p.ident()
+	}
+loop:
+	for !p.done() {
+		switch p.peek() {
+		case '#':
+			p.pos++
+			c.id = p.ident()
+			if c.id == "" {
+				return c, fmt.Errorf("missing id at %d", p.pos)
+			}
+		case '.':
+			p.pos++
+			class := p.ident()
+			if class == "" {
+				return c, fmt.Errorf("missing class at %d", p.pos)
+			}
+			c.classes = append(c.classes, class)
+		case '[':
+			p.pos++
+			a, err := p.attr()
+			if err != nil {
+				return c, err
+			}
+			c.attrs = append(c.attrs, a)
+		case ':':
+			return c, fmt.Errorf("pseudo-classes are not supported")
+		default:
+			break loop
+		}
+	}
+	if c.name == "" && c.id == "" && len(c.classes) == 0 && len(c.attrs) == 0 {
+		return c, fmt.Errorf("expected selector at %d", p.pos)
+	}
+	return c, nil
+}
+
+func (p *selectorParser) attr() (attrSelector, error) {
+	p.spaces()
+	a := attrSelector{name: p.ident()}
+	if a.name == "" {
+		return a, fmt.Errorf("missing attribute name at %d", p.pos)
+	}
+	p.spaces()
+	if p.peek() == ']' {
+		p.pos++
+		return a, nil
+	}
+	for _, op := range []string{"=", "~=", "|=", "^=", "$=", "*="} {
+		if strings.HasPrefix(p.input[p.pos:], op) {
+			a.op = op
+			p.pos += len(op)
+			break
+		}
+	}
+	if a.op == "" {
+		return a, fmt.Errorf("invalid attribute operator at %d", p.pos)
+	}
+	p.spaces()
+	if quote := p.peek(); quote == '"' || quote == '\'' {
+		end := strings.IndexByte(p.input[p.pos+1:], quote)
+		if end < 0 {
+			return a, fmt.Errorf("unterminated string at %d", p.pos)
+		}
+		a.value = p.input[p.pos+1 : p.pos+1+end]
+		p.pos += end + 2
+	} else {
+		a.value = p.ident()
+	}
+	p.spaces()
+	if p.peek() != ']' {
+		return a, fmt.Errorf("missing ']' at %d", p.pos)
+	}
+	p.pos++
+	return a, nil
+}
@@ -0,0 +1,68 @@
+package element_test
+
+import (
+	"testing"
+
+	"github.com/TheWozard/gohtmx/attributes"
+	"github.com/TheWozard/gohtmx/element"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuery(t *testing.T) {
+	button := &element.Tag{Name: "button", Attributes: attributes.New().String("hx-post", "/save").String("id", "save")}
+	link := &element.Tag{Name: "a", Attributes: attributes.New().String("href", "https://example.com/page")}
+	nested := &element.Tag{Name: "button", Attributes: attributes.New().Bool("disabled", true)}
+	card := &element.Tag{Name: "div", Attributes: attributes.New().Strings("class", "card", "primary"), Content: element.Fragment{
+		element.Raw("text"),
+		button,
+		link,
+		&element.Tag{Name: "span", Content: nested},
+	}}
+	other := &element.Tag{Name: "div", Attributes: attributes.New().String("class", "card-list"), Content: element.TBlock{
+		Text:       "if .x",
+		IncludeEnd: true,
+		Element:    &element.Tag{Name: "p"},
+	}}
+	tree := element.Fragment{card, other}
+
+	testCases := []struct {
+		desc     string
+		selector string
+		expected []*element.Tag
+		err      string
+	}{
+		{desc: "type", selector: "button", expected: []*element.Tag{button, nested}},
+		{desc: "universal", selector: "div.card > *", expected: []*element.Tag{button, link, card.Content.(element.Fragment)[3].(*element.Tag)}},
+		{desc: "id", selector: "#save", expected: []*element.Tag{button}},
+		{desc: "class", selector: ".card.primary", expected: []*element.Tag{card}},
+		{desc: "class is whole word", selector: ".card", expected: []*element.Tag{card}},
+		{desc: "attribute", selector: "button[hx-post]", expected: []*element.Tag{button}},
+		{desc: "boolean attribute", selector: "[disabled]", expected: []*element.Tag{nested}},
+		{desc: "attribute value", selector: `[hx-post="/save"]`, expected: []*element.Tag{button}},
+		{desc: "attribute prefix", selector: "a[href^='https://']", expected: []*element.Tag{link}},
+		{desc: "attribute suffix", selector: "a[href$=page]", expected: []*element.Tag{link}},
+		{desc: "attribute contains", selector: "[class*=list]", expected: []*element.Tag{other}},
+		{desc: "attribute dash", selector: "[class|=card]", expected: []*element.Tag{other}},
+		{desc: "descendant", selector: "div.card button", expected: []*element.Tag{button, nested}},
+		{desc: "child", selector: "div.card > button[hx-post]", expected: []*element.Tag{button}},
+		{desc: "adjacent sibling", selector: "button + a", expected: []*element.Tag{link}},
+		{desc: "general sibling", selector: "button ~ span > button", expected: []*element.Tag{nested}},
+		{desc: "through template blocks", selector: "div > p", expected: []*element.Tag{other.Content.(element.TBlock).Element.(*element.Tag)}},
+		{desc: "list in document order", selector: "a, #save", expected: []*element.Tag{button, link}},
+		{desc: "no match", selector: "form", expected: []*element.Tag{}},
+		{desc: "pseudo-class", selector: "button:hover", err: "pseudo-classes are not supported"},
+		{desc: "invalid", selector: "div >", err: "invalid selector 'div >'"},
+		{desc: "unterminated attribute", selector: "[id=a", err: "missing ']'"},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			tags, err := element.Query(tree, tC.selector)
+			if tC.err != "" {
+				require.ErrorContains(t, err, tC.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tC.expected, tags)
+		})
+	}
+}
@@ -35,6 +35,13 @@ func (t TBlock) Render(w io.Writer) error {
 	return nil
 }
 
+func (t TBlock) Children() []Element {
+	if t.Element == nil {
+		return nil
+	}
+	return []Element{t.Element}
+}
+
 func (t TBlock) GetTags() []*Tag {
 	return t.Element.GetTags()
 }
@@ -0,0 +1,66 @@
+package element
+
+import (
+	"errors"
+	"strconv"
+)
+
+// SkipChildren is returned by a WalkFunc entering an Element to skip walking its children.
+var SkipChildren = errors.New("skip children")
+
+// WalkFunc is called for an Element during Walk with the path of the Tag containing it. Paths are the names of the
+// Tags from the root, each followed by its index among its sibling Tags, the same as the path of a PathError.
+type WalkFunc func(e Element, path []string) error
+
+// Walk walks the tree of the Element depth first. enter is called before the children of each Element, and leave
+// after them. Either can be nil. If enter returns SkipChildren, the children are skipped but leave is still called.
+// Any other error stops the walk and is returned.
+func Walk(e Element, enter, leave WalkFunc) error {
+	return walk(e, nil, new(int), enter, leave)
+}
+
+func walk(e Element, path []string, index *int, enter, leave WalkFunc) error {
+	if e == nil {
+		return nil
+	}
+	// Tags are indexed among the Tags of their parent, regardless of the Elements in between.
+	children := index
+	if t, ok := e.(*Tag); ok {
+		path = append(path[:len(path):len(path)], t.Name, "("+strconv.Itoa(*index)+")")
+		*index++
+		children = new(int)
+	}
+	var err error
+	if enter != nil {
+		err = enter(e, path)
+	}
+	if err != nil && !errors.Is(err, SkipChildren) {
+		return err
+	}
+	if err == nil {
+		for _, child := range e.Children() {
+			err = walk(child, path, children, enter, leave)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if leave != nil {
+		err = leave(e, path)
+		if err != nil && !errors.Is(err, SkipChildren) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Visit calls f with every Tag in the tree of the Element and its path. Returning SkipChildren skips the descendants
+// of the Tag.
+func Visit(e Element, f func(t *Tag, path []string) error) error {
+	return Walk(e, func(e Element, path []string) error {
+		if t, ok := e.(*Tag); ok {
+			return f(t, path)
+		}
+		return nil
+	}, nil)
+}
@@ -0,0 +1,105 @@
+package element_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/TheWozard/gohtmx/element"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalk(t *testing.T) {
+	tree := element.Fragment{
+		&element.Tag{Name: "div", Content: element.Fragment{
+			element.Raw("text"),
+			&element.Tag{Name: "span"},
+			element.TBlock{Text: "if .x", IncludeEnd: true, Element: &element.Tag{Name: "button"}},
+		}},
+		&element.Tag{Name: "p", Content: &element.Tag{Name: "a"}},
+	}
+
+	testCases := []struct {
+		desc     string
+		skip     string
+		expected []string
+	}{
+		{
+			desc: "all",
+			expected: []string{
+				"enter element.Fragment ",
+				"enter *element.Tag div(0)",
+				"enter element.Fragment div(0)",
+				"enter element.Raw div(0)",
+				"leave element.Raw div(0)",
+				"enter *element.Tag div(0).span(0)",
+				"leave *element.Tag div(0).span(0)",
+				"enter element.TBlock div(0)",
+				"enter *element.Tag div(0).button(1)",
+				"leave *element.Tag div(0).button(1)",
+				"leave element.TBlock div(0)",
+				"leave element.Fragment div(0)",
+				"leave *element.Tag div(0)",
+				"enter *element.Tag p(1)",
+				"enter *element.Tag p(1).a(0)",
+				"leave *element.Tag p(1).a(0)",
+				"leave *element.Tag p(1)",
+				"leave element.Fragment ",
+			},
+		},
+		{
+			desc: "skip children",
+			skip: "div",
+			expected: []string{
+				"enter element.Fragment ",
+				"enter *element.Tag div(0)",
+				"leave *element.Tag div(0)",
+				"enter *element.Tag p(1)",
+				"enter *element.Tag p(1).a(0)",
+				"leave *element.Tag p(1).a(0)",
+				"leave *element.Tag p(1)",
+				"leave element.Fragment ",
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			visited := []string{}
+			record := func(action string) element.WalkFunc {
+				return func(e element.Element, path []string) error {
+					visited = append(visited, fmt.Sprintf("%s %T %s", action, e, element.PathError{Path: path}.String()))
+					if tag, ok := e.(*element.Tag); ok && action == "enter" && tag.Name == tC.skip {
+						return element.SkipChildren
+					}
+					return nil
+				}
+			}
+			require.NoError(t, element.Walk(tree, record("enter"), record("leave")))
+			require.Equal(t, tC.expected, visited)
+		})
+	}
+}
+
+func TestVisit(t *testing.T) {
+	tree := &element.Tag{Name: "ul", Content: element.Fragment{
+		&element.Tag{Name: "li"},
+		&element.Tag{Name: "li"},
+	}}
+	paths := []string{}
+	err := element.Visit(tree, func(tag *element.Tag, path []string) error {
+		paths = append(paths, strings.Join(path, ""))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"ul(0)", "ul(0)li(0)", "ul(0)li(1)"}, paths)
+
+	stop := errors.New("stop")
+	err = element.Visit(tree, func(tag *element.Tag, path []string) error {
+		if tag.Name == "li" {
+			return stop
+		}
+		return nil
+	})
+	require.ErrorIs(t, err, stop)
+}
@@ -0,0 +1,99 @@
+package gohtmx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// StatusError attaches a http status code to an error.
+type StatusError struct {
+	Code int
+	Err  error
+}
+
+// NewStatusError creates a new StatusError with the given code.
+func NewStatusError(code int, err error) error {
+	return StatusError{Code: code, Err: err}
+}
+
+func (s StatusError) Error() string {
+	if s.Err == nil {
+		return http.StatusText(s.Code)
+	}
+	return s.Err.Error()
+}
+
+func (s StatusError) Unwrap() error {
+	return s.Err
+}
+
+// StatusCode returns the http status code of the error. Errors without a StatusError are internal server errors.
+func StatusCode(err error) int {
+	var se StatusError
+	if errors.As(err, &se) && se.Code != 0 {
+		return se.Code
+	}
+	return http.StatusInternalServerError
+}
+
+// ErrorHandler renders an error in place of the expected response.
+type ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+
+// DefaultErrorHandler writes the status text of the error. The error itself is not written to avoid leaking internals.
+func DefaultErrorHandler(w http.ResponseWriter, _ *http.Request, err error) {
+	code := StatusCode(err)
+	http.Error(w, http.StatusText(code), code)
+}
+
+type errorHandlerKey struct{}
+
+// withErrorHandler stores the ErrorHandler in the context of r so it is accessible to middleware.
+func withErrorHandler(r *http.Request, h ErrorHandler) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), errorHandlerKey{}, h))
+}
+
+// HandleError renders the error through the ErrorHandler of the Page serving the request.
+// This allows middleware to render errors the same way as Handles. If the request is not served through a Page, the
+// DefaultErrorHandler is used.
+func HandleError(w http.ResponseWriter, r *http.Request, err error) {
+	if r != nil {
+		if h, ok := r.Context().Value(errorHandlerKey{}).(ErrorHandler); ok && h != nil {
+			h(w, r, err)
+			return
+		}
+	}
+	DefaultErrorHandler(w, r, err)
+}
+
+// ErrorTemplateHandler renders errors through a template. The template is executed with the error as .error and
+// the status code as .status.
+type ErrorTemplateHandler struct {
+	Template TemplateHandler
+	// Target is the CSS selector errors are swapped into for htmx requests. If empty, the error is returned to the
+	// original target with the status code of the error, which htmx will not swap by default.
+	Target string
+	// Swap is the SwapMethod used to swap the error into the Target.
+	Swap SwapMethod
+}
+
+// ServeError renders the error. If the template fails to execute, the DefaultErrorHandler is used.
+func (e ErrorTemplateHandler) ServeError(w http.ResponseWriter, r *http.Request, err error) {
+	code := StatusCode(err)
+	raw, terr := e.Template.ExecuteWith(r, Data{"error": err, "status": code})
+	if terr != nil {
+		DefaultErrorHandler(w, r, err)
+		return
+	}
+	if e.Target != "" && NewHXRequest(r).Partial() {
+		response := NewResponse(w).Retarget(e.Target)
+		if e.Swap != "" {
+			response.Reswap(e.Swap)
+		}
+		// htmx only swaps successful responses, so the retargeted error is sent as one.
+		code = http.StatusOK
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(code)
+	_, _ = w.Write(raw)
+}
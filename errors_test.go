@@ -0,0 +1,97 @@
+package gohtmx_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TheWozard/gohtmx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorComponent(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		setup    func(p *gohtmx.Page)
+		path     string
+		headers  map[string]string
+		code     int
+		header   http.Header
+		expected string
+	}{
+		{
+			desc: "default error handler",
+			setup: func(p *gohtmx.Page) {
+				p.Handle(func(r *http.Request) (gohtmx.Data, error) {
+					return nil, errors.New("internal details")
+				})
+			},
+			path:     "/",
+			code:     http.StatusInternalServerError,
+			expected: "Internal Server Error\n",
+		},
+		{
+			desc: "handler error",
+			setup: func(p *gohtmx.Page) {
+				p.ErrorComponent = gohtmx.Raw(`{{.status}}: {{.error}}`)
+				p.Handle(func(r *http.Request) (gohtmx.Data, error) {
+					return nil, gohtmx.NewStatusError(http.StatusNotFound, errors.New("missing"))
+				})
+			},
+			path:     "/",
+			code:     http.StatusNotFound,
+			expected: "404: missing",
+		},
+		{
+			desc: "template error",
+			setup: func(p *gohtmx.Page) {
+				p.ErrorComponent = gohtmx.Raw(`{{.status}}`)
+				p.Add(gohtmx.Raw(`{{template "missing"}}`))
+			},
+			path:     "/",
+			code:     http.StatusInternalServerError,
+			expected: "500",
+		},
+		{
+			desc: "retargeted htmx error",
+			setup: func(p *gohtmx.Page) {
+				p.ErrorComponent = gohtmx.Raw(`{{.error}}`)
+				p.ErrorTarget = "#errors"
+				p.ErrorSwap = gohtmx.SwapBeforeEnd
+				p.AtPath("partial").Handle(func(r *http.Request) (gohtmx.Data, error) {
+					return nil, errors.New("failed")
+				})
+			},
+			path:    "/partial",
+			headers: map[string]string{"HX-Request": "true"},
+			code:    http.StatusOK,
+			header: http.Header{
+				"Content-Type": {"text/html; charset=utf-8"},
+				"Hx-Retarget":  {"#errors"},
+				"Hx-Reswap":    {"beforeend"},
+			},
+			expected: "failed",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			p := gohtmx.NewPage()
+			tC.setup(p)
+			h, err := p.Build()
+			require.NoError(t, err)
+
+			r := httptest.NewRequest(http.MethodGet, tC.path, nil)
+			for key, value := range tC.headers {
+				r.Header.Set(key, value)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			require.Equal(t, tC.code, w.Code)
+			require.Equal(t, tC.expected, w.Body.String())
+			if tC.header != nil {
+				require.Equal(t, tC.header, w.Header())
+			}
+		})
+	}
+}
@@ -1,10 +1,16 @@
 package gohtmx
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
 )
 
-// Generator used to generate content for building templates.
+// Generator used to generate content for building templates. Generators must be safe for concurrent use.
 type Generator interface {
 	NewID(group string) string
 }
@@ -15,9 +21,13 @@ func NewDefaultGenerator() Generator {
 
 type IterGenerator struct {
 	Index map[string]int64
+
+	mu sync.Mutex
 }
 
 func (b *IterGenerator) NewID(prefix string) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
 	if b.Index == nil {
 		b.Index = map[string]int64{}
 	}
@@ -25,3 +35,77 @@ func (b *IterGenerator) NewID(prefix string) string {
 	b.Index[prefix] = value + 1
 	return fmt.Sprintf("%s_%d", prefix, value)
 }
+
+// Position is the location of a referenced Component within a Page.
+type Position struct {
+	// Path is the path of the Page the Component is initialized at.
+	Path string
+	// Type is the lower case type name of the Component.
+	Type string
+	// Index is the number of Components of the same Type referenced at the same Path before this one.
+	Index int
+	// Hash is the content hash of the Component as initialized.
+	Hash string
+}
+
+// ElementIDGenerator is a Generator that generates the ids of referenced elements from their Position, rather than
+// the order they are generated in.
+type ElementIDGenerator interface {
+	Generator
+	ElementID(position Position) (string, error)
+}
+
+// NewPathGenerator creates a new PathGenerator.
+func NewPathGenerator() *PathGenerator {
+	return &PathGenerator{Prefix: "gohtmx"}
+}
+
+// PathGenerator generates element ids derived from the path, type and index of the Component, such as
+// "gohtmx-users-div-0". Ids are stable when Components are added at other paths or of other types. With Hash, ids are
+// derived from the content of the Component instead of its index, so they are stable regardless of order.
+// Different Positions generating the same id are reported as an error, which fails the Build.
+type PathGenerator struct {
+	IterGenerator
+	// Prefix is prepended to all element ids.
+	Prefix string
+	// Hash uses the content hash of the Component in place of its index.
+	Hash bool
+
+	idsMu sync.Mutex
+	ids   map[string]Position
+}
+
+var invalidIDChars = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+func (g *PathGenerator) ElementID(position Position) (string, error) {
+	parts := []string{g.Prefix}
+	for _, segment := range strings.Split(position.Path, "/") {
+		if segment != "" {
+			parts = append(parts, invalidIDChars.ReplaceAllString(segment, "_"))
+		}
+	}
+	parts = append(parts, position.Type)
+	if g.Hash {
+		parts = append(parts, position.Hash)
+	} else {
+		parts = append(parts, strconv.Itoa(position.Index))
+	}
+	id := strings.Join(parts, "-")
+
+	g.idsMu.Lock()
+	defer g.idsMu.Unlock()
+	if g.ids == nil {
+		g.ids = map[string]Position{}
+	}
+	if existing, ok := g.ids[id]; ok && existing != position {
+		return "", fmt.Errorf("generated id '%s' collides with %s %d at '%s', set an id explicitly", id, existing.Type, existing.Index, existing.Path)
+	}
+	g.ids[id] = position
+	return id, nil
+}
+
+// contentHash returns a short hash of the content.
+func contentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:4])
+}
@@ -0,0 +1,75 @@
+package gohtmx_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TheWozard/gohtmx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathGenerator(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		hash     bool
+		prepend  gohtmx.Component
+		expected string
+		err      string
+	}{
+		{
+			desc: "index",
+			expected: `<div id="gohtmx-div-0">test</div>` +
+				`<button hx-post="/interaction" hx-swap="outerHTML" hx-target="#gohtmx-div-0" type="button">update</button>`,
+		},
+		{
+			desc:    "index with other types added",
+			prepend: gohtmx.Span{Content: gohtmx.Raw("new")},
+			expected: `<span>new</span>` +
+				`<div id="gohtmx-div-0">test</div>` +
+				`<button hx-post="/interaction" hx-swap="outerHTML" hx-target="#gohtmx-div-0" type="button">update</button>`,
+		},
+		{
+			desc:    "hash with same types added",
+			hash:    true,
+			prepend: gohtmx.Div{Content: gohtmx.Raw("new")},
+			expected: `<div>new</div>` +
+				`<div id="gohtmx-div-270c2933">test</div>` +
+				`<button hx-post="/interaction" hx-swap="outerHTML" hx-target="#gohtmx-div-270c2933" type="button">update</button>`,
+		},
+		{
+			desc: "hash collision",
+			hash: true,
+			prepend: func() gohtmx.Component {
+				other := gohtmx.NewInteraction("other")
+				return gohtmx.Fragment{other, other.Swap().OutOfBand().Update(gohtmx.Div{Content: gohtmx.Raw("test")})}
+			}(),
+			err: "generated id 'gohtmx-div-270c2933' collides with div",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			p := gohtmx.NewPage()
+			generator := gohtmx.NewPathGenerator()
+			generator.Hash = tC.hash
+			p.Generator = generator
+			interaction := gohtmx.NewInteraction("interaction")
+			p.Add(gohtmx.Fragment{
+				tC.prepend,
+				interaction,
+				interaction.Swap().Update(gohtmx.Div{Content: gohtmx.Raw("test")}),
+				interaction.Trigger().Target(gohtmx.Button{Content: gohtmx.Raw("update")}),
+			})
+			h, err := p.Build()
+			if tC.err != "" {
+				require.ErrorContains(t, err, tC.err)
+				return
+			}
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+			require.Equal(t, tC.expected, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package gohtmx
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+
+	"github.com/TheWozard/gohtmx/element"
+)
+
+// ErrNotAllowed is the error rendered when a request is blocked by a Guard.
+var ErrNotAllowed = errors.New("request not allowed")
+
+// Policy decides if a request is authorized.
+type Policy func(*http.Request) bool
+
+// Guard ties authorization to both rendering and serving. Guarded content is omitted from the page when the Policy
+// does not allow the request, and any endpoints mounted by the content reject the request.
+type Guard struct {
+	Policy Policy
+	// Status is the status code of the error rendered for rejected requests. Defaults to http.StatusForbidden, use
+	// http.StatusUnauthorized for unauthenticated clients.
+	Status int
+}
+
+// Allowed returns true if the request passes the Policy. A Guard without a Policy allows all requests.
+func (g Guard) Allowed(r *http.Request) bool {
+	return g.Policy == nil || g.Policy(r)
+}
+
+// Middleware rejects any request not allowed by the Guard through HandleError.
+func (g Guard) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !g.Allowed(r) {
+			status := g.Status
+			if status == 0 {
+				status = http.StatusForbidden
+			}
+			HandleError(w, r, NewStatusError(status, ErrNotAllowed))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// init wraps the content so it is only rendered when the request is allowed.
+func (g Guard) init(p *Page, content element.Element) element.Element {
+	if g.Policy == nil {
+		return content
+	}
+	id := p.Generator.NewID("guard")
+	p.funcs(template.FuncMap{id: g.Allowed})
+	return element.TBlock{
+		Text:       fmt.Sprintf(`if %s $r`, id),
+		IncludeEnd: true,
+		Element:    content,
+	}
+}
+
+// Guarded renders the Content only for requests allowed by the Guard. Interactions, EventStreams and Sockets within
+// the Content reject requests not allowed by the Guard.
+type Guarded struct {
+	Guard   Guard
+	Content Component
+}
+
+func (g Guarded) Init(p *Page) (element.Element, error) {
+	return g.Guard.init(p, p.WithGuard(g.Guard).Init(g.Content)), nil
+}
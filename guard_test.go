@@ -0,0 +1,111 @@
+package gohtmx_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TheWozard/gohtmx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGuard(t *testing.T) {
+	admin := gohtmx.Guard{Policy: func(r *http.Request) bool {
+		return r.Header.Get("X-User") == "admin"
+	}}
+	user := gohtmx.Guard{Status: http.StatusUnauthorized, Policy: func(r *http.Request) bool {
+		return r.Header.Get("X-User") != ""
+	}}
+
+	p := gohtmx.NewPage()
+	remove := gohtmx.NewInteraction("remove")
+	view := gohtmx.NewInteraction("view")
+	p.Add(gohtmx.Fragment{
+		gohtmx.Guarded{
+			Guard: admin,
+			Content: gohtmx.Fragment{
+				remove,
+				remove.Trigger().Target(gohtmx.Button{Content: gohtmx.Raw("remove")}),
+			},
+		},
+		gohtmx.MetaScope{
+			Path:  "account",
+			Guard: &user,
+			Content: gohtmx.Fragment{
+				view,
+				view.Trigger().Target(gohtmx.Button{Content: gohtmx.Raw("view")}),
+			},
+		},
+	})
+	h, err := p.Build()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc     string
+		user     string
+		path     string
+		code     int
+		expected string
+	}{
+		{
+			desc: "anonymous page",
+			path: "/",
+			code: http.StatusOK,
+		},
+		{
+			desc:     "user page",
+			user:     "user",
+			path:     "/",
+			code:     http.StatusOK,
+			expected: `<button hx-post="/account/view" hx-swap="none" type="button">view</button>`,
+		},
+		{
+			desc: "admin page",
+			user: "admin",
+			path: "/",
+			code: http.StatusOK,
+			expected: `<button hx-post="/remove" hx-swap="none" type="button">remove</button>` +
+				`<button hx-post="/account/view" hx-swap="none" type="button">view</button>`,
+		},
+		{
+			desc:     "user interaction",
+			user:     "user",
+			path:     "/remove",
+			code:     http.StatusForbidden,
+			expected: "Forbidden\n",
+		},
+		{
+			desc: "admin interaction",
+			user: "admin",
+			path: "/remove",
+			code: http.StatusOK,
+		},
+		{
+			desc:     "anonymous scoped interaction",
+			path:     "/account/view",
+			code:     http.StatusUnauthorized,
+			expected: "Unauthorized\n",
+		},
+		{
+			desc: "user scoped interaction",
+			user: "user",
+			path: "/account/view",
+			code: http.StatusOK,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, tC.path, nil)
+			if tC.path != "/" {
+				r.Header.Set("HX-Request", "true")
+			}
+			if tC.user != "" {
+				r.Header.Set("X-User", tC.user)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			require.Equal(t, tC.code, w.Code)
+			require.Equal(t, tC.expected, w.Body.String())
+		})
+	}
+}
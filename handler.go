@@ -2,14 +2,44 @@ package gohtmx
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"html/template"
 	"net/http"
 )
 
+// HandlerResult is the combined result of all Handles that have been called for a request.
+type HandlerResult struct {
+	Data Data
+	Err  error
+}
+
+type handlerResultKey struct{}
+
+// GetHandlerResult returns the HandlerResult of the request. If no Handle has been called, the result is empty.
+func GetHandlerResult(r *http.Request) HandlerResult {
+	if r != nil {
+		if result, ok := r.Context().Value(handlerResultKey{}).(*HandlerResult); ok {
+			return *result
+		}
+	}
+	return HandlerResult{}
+}
+
+// withHandlerResult ensures the request has a HandlerResult in its context.
+func withHandlerResult(r *http.Request) (*http.Request, *HandlerResult) {
+	if result, ok := r.Context().Value(handlerResultKey{}).(*HandlerResult); ok {
+		return r, result
+	}
+	result := &HandlerResult{Data: Data{}}
+	return r.WithContext(context.WithValue(r.Context(), handlerResultKey{}, result)), result
+}
+
 type TemplateHandler struct {
 	Template *template.Template
 	Name     string
+	// ErrorHandler renders any errors from Handles or template execution. Defaults to HandleError.
+	ErrorHandler ErrorHandler
 }
 
 func (t TemplateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -17,19 +47,40 @@ func (t TemplateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 }
 
 func (t TemplateHandler) ServeHTTPWithData(w http.ResponseWriter, r *http.Request, data Data) {
+	err := GetHandlerResult(r).Err
+	if err != nil {
+		t.error(w, r, err)
+		return
+	}
 	raw, err := t.ExecuteWith(r, data)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		t.error(w, r, err)
 		return
 	}
 	_, _ = w.Write(raw)
 }
 
+// ExecuteWith executes the template with the data. The Data of any Handles is merged in first, with the passed data
+// taking priority.
 func (t TemplateHandler) ExecuteWith(r *http.Request, data Data) ([]byte, error) {
+	values := Data{}
+	for _, d := range []Data{GetHandlerResult(r).Data, data, {"request": r, "hx": NewHXRequest(r)}} {
+		for key, value := range d {
+			values[key] = value
+		}
+	}
 	buffer := bytes.NewBuffer(nil)
-	err := t.Template.ExecuteTemplate(buffer, t.Name, data.Merge(Data{"request": r}))
+	err := t.Template.ExecuteTemplate(buffer, t.Name, values)
 	if err != nil {
 		return nil, fmt.Errorf(`failed to render template %s: %w`, t.Name, err)
 	}
 	return buffer.Bytes(), err
 }
+
+func (t TemplateHandler) error(w http.ResponseWriter, r *http.Request, err error) {
+	if t.ErrorHandler != nil {
+		t.ErrorHandler(w, r, err)
+		return
+	}
+	HandleError(w, r, err)
+}
@@ -0,0 +1,146 @@
+package gohtmx
+
+import (
+	"io"
+
+	"github.com/TheWozard/gohtmx/attributes"
+	"github.com/TheWozard/gohtmx/element"
+)
+
+// HeadTag is a tag placed in the <head> of the document by a Component anywhere in the tree.
+// Tags with the same Key are deduplicated, keeping the position of the first and the content of the last.
+type HeadTag struct {
+	Key string
+	Tag *element.Tag
+}
+
+// Title sets the <title> of the current path. Title may contain template expressions.
+type Title string
+
+func (t Title) Init(p *Page) (element.Element, error) {
+	p.head(HeadTag{Key: "title", Tag: &element.Tag{Name: "title", Content: element.Raw(t)}})
+	return nil, nil
+}
+
+// Meta sets a <meta> tag of the current path, identified by either its Name or Property.
+type Meta struct {
+	Name     string
+	Property string
+	Content  string
+}
+
+func (m Meta) Init(p *Page) (element.Element, error) {
+	key := "meta name=" + m.Name
+	if m.Property != "" {
+		key = "meta property=" + m.Property
+	}
+	p.head(HeadTag{Key: key, Tag: &element.Tag{
+		Name: "meta",
+		Attributes: attributes.New().
+			String("name", m.Name).
+			String("property", m.Property).
+			String("content", m.Content),
+	}})
+	return nil, nil
+}
+
+// Link sets a <link> tag of the current path, such as a stylesheet. Links are identified by their Rel and Href.
+type Link struct {
+	Rel   string
+	Href  string
+	Attrs *attributes.Attributes
+}
+
+func (l Link) Init(p *Page) (element.Element, error) {
+	p.head(HeadTag{Key: "link " + l.Rel + " " + l.Href, Tag: &element.Tag{
+		Name:       "link",
+		Attributes: l.Attrs.Ensure().Copy().String("rel", l.Rel).String("href", l.Href),
+	}})
+	return nil, nil
+}
+
+// Canonical sets the canonical URL of the current path. There is only ever one canonical link.
+type Canonical string
+
+func (c Canonical) Init(p *Page) (element.Element, error) {
+	p.head(HeadTag{Key: "link canonical", Tag: &element.Tag{
+		Name:       "link",
+		Attributes: attributes.New().String("rel", "canonical").String("href", string(c)),
+	}})
+	return nil, nil
+}
+
+// head adds the HeadTag to the request at this pages current path.
+func (p *Page) head(tag HeadTag) {
+	p.modify(p.Path(), func(request *Request) {
+		request.Head = append(request.Head, tag)
+	})
+}
+
+// mergeHead deduplicates the tags by their Key.
+func mergeHead(tags []HeadTag) []*element.Tag {
+	index := map[string]int{}
+	merged := []*element.Tag{}
+	for _, tag := range tags {
+		if i, ok := index[tag.Key]; ok {
+			merged[i] = tag.Tag
+			continue
+		}
+		index[tag.Key] = len(merged)
+		merged = append(merged, tag.Tag)
+	}
+	return merged
+}
+
+// headElement renders the merged HeadTags of a path. The tags are only known once all Components of the path are
+// initialized, so they are read when rendered.
+type headElement struct {
+	page *Page
+	path string
+}
+
+func (h headElement) Render(w io.Writer) error {
+	for _, tag := range mergeHead(h.page.request(h.path).Head) {
+		err := tag.Render(w)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h headElement) Validate() error {
+	return nil
+}
+
+func (h headElement) Children() []element.Element {
+	tags := mergeHead(h.page.request(h.path).Head)
+	children := make([]element.Element, len(tags))
+	for i, tag := range tags {
+		children[i] = tag
+	}
+	return children
+}
+
+func (h headElement) GetTags() []*element.Tag {
+	return mergeHead(h.page.request(h.path).Head)
+}
+
+// partialHead returns the Element of the HeadTags of a partial response. htmx updates the title from any <title> in a
+// response, while all other tags are sent in a <head> to be merged by the head-support extension.
+// See https://htmx.org/extensions/head-support/
+func partialHead(tags []HeadTag, headSupport bool) element.Element {
+	head := element.Fragment{}
+	content := element.Fragment{}
+	for _, tag := range mergeHead(tags) {
+		if tag.Name == "title" {
+			head = append(head, tag)
+		} else if headSupport {
+			content = append(content, tag)
+		}
+	}
+	if len(content) > 0 {
+		head = append(head, &element.Tag{Name: "head", Content: content})
+	}
+	return head
+}
@@ -0,0 +1,88 @@
+package gohtmx_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TheWozard/gohtmx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHead(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		headSupport bool
+		hx          bool
+		path        string
+		expected    string
+	}{
+		{
+			desc: "document",
+			path: "/",
+			expected: `<!DOCTYPE html><html><head>` +
+				`<title>Home</title>` +
+				`<meta content="gohtmx" name="description"></meta>` +
+				`<link href="/app.css" rel="stylesheet"></link>` +
+				`<link href="https://example.com/" rel="canonical"></link>` +
+				`</head><body>home</body></html>`,
+		},
+		{
+			desc:     "partial",
+			hx:       true,
+			path:     "/users",
+			expected: `<title>Users</title>users`,
+		},
+		{
+			desc:        "partial head support",
+			headSupport: true,
+			hx:          true,
+			path:        "/users",
+			expected:    `<title>Users</title><head><meta content="all users" name="description"></meta></head>users`,
+		},
+		{
+			desc:        "document head support",
+			headSupport: true,
+			path:        "/",
+			expected: `<!DOCTYPE html><html><head>` +
+				`<title>Home</title>` +
+				`<meta content="gohtmx" name="description"></meta>` +
+				`<link href="/app.css" rel="stylesheet"></link>` +
+				`<link href="https://example.com/" rel="canonical"></link>` +
+				`</head><body hx-ext="head-support">home</body></html>`,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			p := gohtmx.NewPage()
+			p.HeadSupport = tC.headSupport
+			p.Add(gohtmx.Document{
+				Body: gohtmx.Fragment{
+					gohtmx.Title("Default"),
+					gohtmx.Meta{Name: "description", Content: "gohtmx"},
+					gohtmx.Link{Rel: "stylesheet", Href: "/app.css"},
+					gohtmx.Canonical("https://example.com/old"),
+					gohtmx.Raw("home"),
+					gohtmx.Link{Rel: "stylesheet", Href: "/app.css"},
+					gohtmx.Canonical("https://example.com/"),
+					gohtmx.Title("Home"),
+				},
+			})
+			p.AtPath("users").Add(gohtmx.Fragment{
+				gohtmx.Title("Users"),
+				gohtmx.Meta{Name: "description", Content: "all users"},
+				gohtmx.Raw("users"),
+			})
+			h, err := p.Build()
+			require.NoError(t, err)
+
+			r := httptest.NewRequest(http.MethodGet, tC.path, nil)
+			if tC.hx {
+				r.Header.Set("HX-Request", "true")
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			require.Equal(t, tC.expected, w.Body.String())
+		})
+	}
+}
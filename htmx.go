@@ -0,0 +1,127 @@
+package gohtmx
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+
+	"github.com/TheWozard/gohtmx/element"
+)
+
+//go:generate go run ./internal/vendor
+
+// HTMXVersion is the version of htmx vendored into static by go generate.
+const HTMXVersion = "1.9.12"
+
+// DefaultHTMXPath is the path the embedded htmx is served under.
+const DefaultHTMXPath = "/_gohtmx/"
+
+//go:embed static
+var static embed.FS
+
+// NewHTMXAssets creates Assets serving the embedded htmx and its extensions under the path.
+func NewHTMXAssets(path string) *Assets {
+	fsys, err := fs.Sub(static, "static")
+	if err != nil {
+		// static is always embedded, so this cannot happen.
+		panic(err)
+	}
+	return NewAssets(path, fsys)
+}
+
+// HTMXScripts renders script tags for htmx and the named extensions, served from the HTMX Assets of the Page.
+// Extensions are named by their htmx name, such as "sse", "ws" or "head-support". Extensions used by Components of
+// the Page, such as the sse extension of an EventStream, are included as well.
+type HTMXScripts struct {
+	Extensions []string
+}
+
+func (h HTMXScripts) Init(p *Page) (element.Element, error) {
+	if p.HTMX == nil {
+		return nil, fmt.Errorf("page has no htmx assets")
+	}
+	p.mount(p.HTMX)
+	return htmxScripts{page: p, extensions: h.Extensions}, nil
+}
+
+// useExtension includes the named htmx extension in every HTMXScripts of the Page.
+func (p *Page) useExtension(name string) {
+	if p.sync == nil {
+		return
+	}
+	p.sync.index.Lock()
+	defer p.sync.index.Unlock()
+	for _, extension := range p.sync.extensions {
+		if extension == name {
+			return
+		}
+	}
+	p.sync.extensions = append(p.sync.extensions, name)
+}
+
+// htmxScripts renders the scripts of htmx. Extensions can be used by Components until the Page is validated, so the
+// scripts are resolved when rendered.
+type htmxScripts struct {
+	page       *Page
+	extensions []string
+}
+
+func (h htmxScripts) scripts() (element.Fragment, error) {
+	names := []string{"htmx.min.js"}
+	seen := map[string]bool{}
+	extensions := h.extensions
+	if h.page.sync != nil {
+		h.page.sync.index.RLock()
+		extensions = append(extensions[:len(extensions):len(extensions)], h.page.sync.extensions...)
+		h.page.sync.index.RUnlock()
+	}
+	for _, extension := range extensions {
+		if !seen[extension] {
+			seen[extension] = true
+			names = append(names, "ext/"+extension+".js")
+		}
+	}
+	scripts := make(element.Fragment, len(names))
+	for i, name := range names {
+		url, err := h.page.HTMX.URL(name)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%s is not vendored, run go generate: %w", name, err)
+		}
+		if err != nil {
+			return nil, err
+		}
+		scripts[i] = h.page.Init(Script{Src: url})
+	}
+	return scripts, nil
+}
+
+func (h htmxScripts) Render(w io.Writer) error {
+	scripts, err := h.scripts()
+	if err != nil {
+		return err
+	}
+	return scripts.Render(w)
+}
+
+func (h htmxScripts) Validate() error {
+	_, err := h.scripts()
+	return err
+}
+
+func (h htmxScripts) GetTags() []*element.Tag {
+	scripts, err := h.scripts()
+	if err != nil {
+		return nil
+	}
+	return scripts.GetTags()
+}
+
+func (h htmxScripts) Children() []element.Element {
+	scripts, err := h.scripts()
+	if err != nil {
+		return nil
+	}
+	return []element.Element{scripts}
+}
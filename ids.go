@@ -0,0 +1,82 @@
+package gohtmx
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/TheWozard/gohtmx/element"
+)
+
+// ErrDuplicateID is reported by Validate for every occurrence of an id used by multiple elements of a request.
+var ErrDuplicateID = errors.New("duplicate id")
+
+// ErrDanglingReference is reported by Validate for every element referencing an id, such as through hx-target, that
+// is not rendered by the request or any request at an outer path.
+var ErrDanglingReference = errors.New("dangling reference")
+
+// referenceAttrs are the attributes referencing other elements through a CSS selector.
+var referenceAttrs = []string{"hx-target", "hx-include", "hx-indicator"}
+
+// idSelector matches CSS selectors of a single id.
+var idSelector = regexp.MustCompile(`^#[A-Za-z0-9_-]+$`)
+
+// collectIDs returns the paths of every element with an id, by id.
+func collectIDs(e element.Element) map[string][][]string {
+	ids := map[string][][]string{}
+	_ = element.Visit(e, func(tag *element.Tag, path []string) error {
+		if id, ok := tag.Attributes.Get("id"); ok {
+			ids[id] = append(ids[id], path)
+		}
+		return nil
+	})
+	return ids
+}
+
+// danglingReferences returns an error for every id referenced by the element that is not in the ids. Only selectors
+// of a single id are checked, as other selectors, such as "closest form", cannot be resolved ahead of time.
+func danglingReferences(e element.Element, ids map[string][][]string) []error {
+	errs := []error{}
+	_ = element.Visit(e, func(tag *element.Tag, path []string) error {
+		for _, attr := range referenceAttrs {
+			value, ok := tag.Attributes.Get(attr)
+			if !ok {
+				continue
+			}
+			for _, selector := range strings.Split(value, ",") {
+				selector = strings.TrimSpace(selector)
+				if !idSelector.MatchString(selector) {
+					continue
+				}
+				if _, ok := ids[selector[1:]]; !ok {
+					errs = append(errs, element.PathError{
+						Path: path,
+						Err:  fmt.Errorf("%w %s='%s'", ErrDanglingReference, attr, selector),
+					})
+				}
+			}
+		}
+		return nil
+	})
+	return errs
+}
+
+// duplicateIDs returns an error for every occurrence of an id used by multiple elements.
+func duplicateIDs(ids map[string][][]string) []error {
+	names := make([]string, 0, len(ids))
+	for id, paths := range ids {
+		if len(paths) > 1 {
+			names = append(names, id)
+		}
+	}
+	sort.Strings(names)
+	errs := []error{}
+	for _, id := range names {
+		for _, path := range ids[id] {
+			errs = append(errs, element.PathError{Path: path, Err: fmt.Errorf("%w '%s'", ErrDuplicateID, id)})
+		}
+	}
+	return errs
+}
@@ -2,8 +2,8 @@ package gohtmx
 
 import (
 	"fmt"
-	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/TheWozard/gohtmx/attributes"
@@ -92,10 +92,13 @@ func NewInteraction(name string) *Interaction {
 type Interaction struct {
 	Name string
 
-	handler  func(*http.Request)
-	swaps    []*Swap
-	triggers []*Trigger
-	page     *Page
+	handler    Handle
+	writes     []Observable
+	dependents map[*Reference]bool
+	mounted    mount
+	swaps      []*Swap
+	triggers   []*Trigger
+	page       *Page
 }
 
 func (i *Interaction) Init(p *Page) (element.Element, error) {
@@ -149,8 +152,9 @@ func (i *Interaction) AddSwap(a *Swap) *Interaction {
 	return i
 }
 
-// Handle adds a handler to the Interaction.
-func (i *Interaction) Handle(f func(*http.Request)) *Interaction {
+// Handle adds a handler to the Interaction. The handler is called before the swaps are rendered, any returned Data is
+// available to the swap templates and any returned error is rendered in place of the swaps.
+func (i *Interaction) Handle(f Handle) *Interaction {
 	if i == nil {
 		return nil
 	}
@@ -158,26 +162,65 @@ func (i *Interaction) Handle(f func(*http.Request)) *Interaction {
 	return i
 }
 
-func (i *Interaction) update() error {
+// Writes declares the Interaction changes the Observables. Every Component depending on them is swapped out of band.
+func (i *Interaction) Writes(o ...Observable) *Interaction {
 	if i == nil {
 		return nil
 	}
-	for _, swap := range i.swaps {
-		err := swap.update(i.page)
-		if err != nil {
-			return err
-		}
+	i.writes = append(i.writes, o...)
+	return i
+}
+
+// update is called during validation.
+func (i *Interaction) update() error {
+	if i == nil {
+		return nil
 	}
+	return i.mounted.apply(i.apply)
+}
+
+func (i *Interaction) apply() error {
 	var swap *Swap
 	if len(i.swaps) > 0 {
 		swap = i.swaps[len(i.swaps)-1]
 	}
+	// Socket responses have no target to swap into, so the in band swap is sent out of band for socket triggers.
+	sockets, requests := false, false
+	for _, trigger := range i.triggers {
+		if trigger.socket != nil {
+			sockets = true
+		} else {
+			requests = true
+		}
+	}
+	if sockets && !requests {
+		swap.OutOfBand()
+	} else if sockets && swap != nil {
+		swap.socket = true
+	}
+	// When every swap is out of band, the triggers do not swap in band.
+	if swap != nil && swap.outOfBand {
+		swap = nil
+	}
+	for _, s := range i.swaps {
+		err := s.update(i.page)
+		if err != nil {
+			return err
+		}
+	}
 	page := i.page.AtPath(i.Name)
-	contents := make(Fragment, len(i.swaps))
+	page.useGuards()
+	contents := make(element.Fragment, len(i.swaps))
 	for j, s := range i.swaps {
-		contents[j] = s.contents
+		var err error
+		contents[j], err = s.rendered()
+		if err != nil {
+			return err
+		}
 	}
-	page.Add(contents)
+	page.modify(page.Path(), func(request *Request) {
+		request.Elements = append(request.Elements, contents)
+	})
 	page.Handle(i.handler)
 	for _, trigger := range i.triggers {
 		err := trigger.update(page, swap)
@@ -185,6 +228,57 @@ func (i *Interaction) update() error {
 			return err
 		}
 	}
+	if len(i.writes) > 0 {
+		page.onValidated(i.swapDependents)
+	}
+	return nil
+}
+
+// swapDependents swaps every Component depending on the written Observables out of band. Components can depend on
+// an Observable from anywhere in the Page, so they are collected once all paths are validated.
+func (i *Interaction) swapDependents() error {
+	if i.dependents == nil {
+		// Dependents the Interaction already swaps itself are not swapped again.
+		i.dependents = map[*Reference]bool{}
+		for _, s := range i.swaps {
+			for ref := s.target; ref != nil; ref, _ = ref.Initialized.(*Reference) {
+				i.dependents[ref] = true
+			}
+		}
+	}
+	path := i.page.Path(i.Name)
+	swaps := element.Fragment{}
+	for _, o := range i.writes {
+		for _, ref := range o.Dependents() {
+			// Dependents that were never added to the page have nothing to swap.
+			if ref.Initialized == nil || i.dependents[ref] {
+				continue
+			}
+			i.dependents[ref] = true
+			_, err := ref.ID()
+			if err != nil {
+				return err
+			}
+			a, err := ref.FindAttrs()
+			if err != nil {
+				return err
+			}
+			// The dependent is rendered where it is added too, where it is not swapped.
+			a.If(fmt.Sprintf("eq $r.URL.Path %q", path), "hx-swap-oob", string(SwapOuterHTML))
+			e, err := ref.outer()
+			if err != nil {
+				return err
+			}
+			swaps = append(swaps, e)
+		}
+	}
+	if len(swaps) == 0 {
+		return nil
+	}
+	// Prepend so the swaps defined on the Interaction stay last, keeping the potential to be in band.
+	i.page.modify(path, func(request *Request) {
+		request.Elements = append(swaps, request.Elements...)
+	})
 	return nil
 }
 
@@ -203,6 +297,8 @@ type Swap struct {
 	contents  *Reference
 	method    SwapMethod
 	outOfBand bool
+	// socket marks an in band swap that is sent out of band when the Interaction is triggered through a Socket.
+	socket bool
 }
 
 // Method sets the swap method to replace the target with.
@@ -287,6 +383,19 @@ func (s *Swap) update(p *Page) error {
 		}
 	}
 
+	if s.socket {
+		ca, err := s.contents.FindAttrs()
+		if err != nil {
+			return err
+		}
+		tid, err := s.target.ID()
+		if err != nil {
+			return err
+		}
+		// The target is selected explicitly, so the contents keep their own id when swapped in band.
+		method := strings.Fields(string(s.method) + " " + string(SwapOuterHTML))[0]
+		ca.If("$hx.Socket", "hx-swap-oob", method+":#"+tid)
+	}
 	if !s.outOfBand {
 		return nil
 	}
@@ -303,11 +412,21 @@ func (s *Swap) update(p *Page) error {
 		if err != nil {
 			return err
 		}
-		ca.String("id", tid)
+		// Delete ensures the id is not duplicated when the content is also the target.
+		ca.Delete("id").String("id", tid)
 	}
 	return nil
 }
 
+// rendered returns the element rendered for the contents. An outerHTML swap replaces only the anchored tag of the
+// contents, see Reference.outer.
+func (s *Swap) rendered() (element.Element, error) {
+	if s.method != SwapOuterHTML {
+		return s.contents, nil
+	}
+	return s.contents.outer()
+}
+
 func (s *Swap) triggerAttrs(a *attributes.Attributes) error {
 	if s == nil {
 		a.String("hx-swap", string(SwapNone))
@@ -334,6 +453,7 @@ func NewTrigger() *Trigger {
 type Trigger struct {
 	target *Reference
 	method TriggerMethod
+	socket *Socket
 	Values url.Values
 }
 
@@ -358,6 +478,16 @@ func (t *Trigger) Method(m TriggerMethod) *Trigger {
 	return t
 }
 
+// Socket sends the Trigger over the Socket instead of a http request. The target needs to be within a Component
+// connected to the Socket.
+func (t *Trigger) Socket(s *Socket) *Trigger {
+	if t == nil {
+		return nil
+	}
+	t.socket = s
+	return t
+}
+
 func (t *Trigger) Set(key, value string) *Trigger {
 	if t == nil {
 		return nil
@@ -374,6 +504,18 @@ func (t *Trigger) update(p *Page, swap *Swap) error {
 	if err != nil {
 		return err
 	}
+	if t.socket != nil {
+		// The socket identifies the Interaction by the id of the sending element.
+		var id string
+		id, err = t.target.ID()
+		if err != nil {
+			return err
+		}
+		t.socket.register(id, t.path(p), p)
+		a.Bool("ws-send", true)
+		a.String("hx-trigger", string(t.method))
+		return nil
+	}
 	a.String("hx-post", t.path(p))
 	a.String("hx-trigger", string(t.method))
 	return swap.triggerAttrs(a)
@@ -1,9 +1,13 @@
 package gohtmx_test
 
 import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/TheWozard/gohtmx"
+	"github.com/stretchr/testify/require"
 )
 
 func TestInteraction(t *testing.T) {
@@ -23,10 +27,10 @@ func TestInteraction(t *testing.T) {
 				})
 			},
 			rendered: map[string]string{
-				"/": `{{$r := .request}}` +
+				"/": `{{$r := .request}}{{$hx := .hx}}` +
 					`<div id="gohtmx_0">test</div>` +
 					`<button hx-post="/interaction" hx-swap="outerHTML" hx-target="#gohtmx_0" type="button">update</button>`,
-				"/interaction": `{{$r := .request}}` +
+				"/interaction": `{{$r := .request}}{{$hx := .hx}}` +
 					`<div id="gohtmx_0">test</div>`,
 			},
 		},
@@ -35,3 +39,52 @@ func TestInteraction(t *testing.T) {
 		tC.Assert(t)
 	}
 }
+
+func TestInteractionHandle(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		handle   gohtmx.Handle
+		expected string
+	}{
+		{
+			desc: "handler data",
+			handle: func(r *http.Request) (gohtmx.Data, error) {
+				return gohtmx.Data{"value": "handled"}, nil
+			},
+			expected: `<div id="gohtmx_0">handled</div>`,
+		},
+		{
+			desc: "handler error",
+			handle: func(r *http.Request) (gohtmx.Data, error) {
+				return nil, errors.New("failed")
+			},
+			expected: `error: failed`,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			p := gohtmx.NewPage()
+			p.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+				_, _ = w.Write([]byte("error: " + err.Error()))
+			}
+			interaction := gohtmx.NewInteraction("interaction").Handle(tC.handle)
+			p.Add(gohtmx.Fragment{
+				interaction,
+				interaction.Swap().Update(gohtmx.Div{
+					Content: gohtmx.Raw("{{.value}}"),
+				}),
+				interaction.Trigger().Target(gohtmx.Button{
+					Content: gohtmx.Raw("update"),
+				}),
+			})
+			h, err := p.Build()
+			require.NoError(t, err)
+
+			r := httptest.NewRequest(http.MethodPost, "/interaction", nil)
+			r.Header.Set("HX-Request", "true")
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			require.Equal(t, tC.expected, w.Body.String())
+		})
+	}
+}
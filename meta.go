@@ -6,8 +6,16 @@ import "github.com/TheWozard/gohtmx/element"
 type MetaScope struct {
 	Path    string
 	Content Component
+	// Guard optionally restricts the scope. The Content is omitted and all requests beneath the path are rejected for
+	// requests not allowed by the Guard.
+	Guard *Guard
 }
 
 func (s MetaScope) Init(p *Page) (element.Element, error) {
-	return s.Content.Init(p.AtPath(s.Path))
+	page := p.AtPath(s.Path)
+	if s.Guard == nil {
+		return s.Content.Init(page)
+	}
+	page.UseAll(s.Guard.Middleware)
+	return s.Guard.init(page, page.Init(s.Content)), nil
 }
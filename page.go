@@ -5,9 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"html/template"
+	"log/slog"
 	"net/http"
+	gopath "path"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/TheWozard/gohtmx/element"
 )
@@ -16,12 +19,44 @@ func NewPage() *Page {
 	return &Page{
 		PathPrefix: "/",
 		Index:      map[string]Request{},
-		Template:   template.New("content"),
+		Template:   template.New("content").Funcs(templateFuncs),
 		Generator:  NewDefaultGenerator(),
+		HTMX:       NewHTMXAssets(DefaultHTMXPath),
+		sync:       &pageSync{},
 	}
 }
 
+// templateFuncs are the functions available to the templates of every Page.
+var templateFuncs = template.FuncMap{
+	// session returns the value stored in the session of the request under the key.
+	"session": func(r *http.Request, key string) (any, error) {
+		var value any
+		session := GetSession(r)
+		if session == nil {
+			return nil, nil
+		}
+		_, err := session.Get(key, &value)
+		return value, err
+	},
+	// csrf returns the CSRF token of the request.
+	"csrf": CSRFToken,
+	// asset returns the fingerprinted URL of a file. Replaced once the Page serves static files.
+	"asset": func(name string) (string, error) {
+		return "", fmt.Errorf("no static assets for '%s'", name)
+	},
+	// nonce returns the CSP nonce of the request.
+	"nonce": CSPNonce,
+	// csrfHeaders returns the CSRF token of the request as a JSON object for hx-headers.
+	"csrfHeaders": csrfHeaders,
+}
+
 // Page defines a single page application.
+//
+// Components can be added to a Page, and any copies of it created through AtPath, from multiple goroutines. The Index
+// and Generator of the Page are synchronized, and functions are only added to the Template under a lock until it is
+// built. Validate, Render and Build must not be called while Components are still being added. Paths are validated in
+// order, so generated ids do not depend on scheduling, while the checks after validation and rendering run concurrently
+// through Concurrency.
 type Page struct {
 	// PathPrefix defines the current prefix for a component to build requests from.
 	PathPrefix string
@@ -31,20 +66,153 @@ type Page struct {
 	Template *template.Template
 	// Generator provides generated content for initializing elements.
 	Generator Generator
+	// ErrorHandler renders errors returned by handlers, template execution and middleware using HandleError.
+	// If not set, the ErrorComponent is used, or DefaultErrorHandler if there is no ErrorComponent.
+	ErrorHandler ErrorHandler
+	// ErrorComponent is rendered in place of the response when an error occurs.
+	// The error is available as .error and its status code as .status, along with the usual $r and $hx.
+	// Use $hx.Request to render a full document for page requests and a fragment for swaps.
+	ErrorComponent Component
+	// ErrorTarget is the CSS selector the ErrorComponent is swapped into for htmx requests, such as a toast or banner.
+	// If empty, htmx requests receive the ErrorComponent with the status of the error.
+	ErrorTarget string
+	// ErrorSwap is the SwapMethod used to swap the ErrorComponent into the ErrorTarget.
+	ErrorSwap SwapMethod
+	// Logger receives panics recovered while serving the page. Defaults to slog.Default.
+	Logger *slog.Logger
+	// Guards are applied to every endpoint mounted from this Page, such as Interactions.
+	Guards []Guard
+	// HTMX serves the embedded htmx when included by a Document. Set before adding any Document to change the path.
+	HTMX *Assets
+	// Assets serves the static files of the Page. Set through Static.
+	Assets *Assets
+	// HeadSupport enables the htmx head-support extension. Partial responses then include their HeadTags in a <head>.
+	// Set before adding any Document.
+	HeadSupport bool
+	// Concurrency is the number of paths checked and rendered at once after they are validated. Defaults to 1.
+	Concurrency int
+	// StrictHTML checks every path against the HTML content model while validating. See element.ValidateHTML.
+	StrictHTML bool
+	// Accessibility checks every path against accessibility rules while validating. Nil disables the checks.
+	Accessibility *Accessibility
+	// AnchorWrap is the name of the tag Components without a single tag are wrapped in when referenced, such as by the
+	// target of a Swap. If empty, referencing such a Component without an Anchor is an error.
+	AnchorWrap string
+	// RenderOptions formats the rendered templates, such as pretty printing for development or minifying for
+	// production. See element.RenderOptions.
+	RenderOptions element.RenderOptions
+
+	sync *pageSync
+}
+
+// pageSync synchronizes a Page and all copies created through AtPath.
+type pageSync struct {
+	// index guards the Index.
+	index sync.RWMutex
+	// positions counts the Components referenced at each path by type, guarded by index.
+	positions map[string]int
+	// extensions are the htmx extensions used by Components, guarded by index.
+	extensions []string
+	// validated are called once all paths are validated, guarded by index.
+	validated []validatedHook
+	// template guards adding functions to the Template.
+	template sync.Mutex
 }
 
 // Validate will validate all elements in the page. Each path is validated individually, and paths with errors are
 // returned in a map. If no errors are found, nil is returned.
+// Validation can add new paths to the page, such as the paths of Interactions. These are validated as they are found.
+// Once all paths are validated, every path is checked for ids used by multiple elements, see ErrDuplicateID,
+// references to ids that are not rendered, see ErrDanglingReference, and assets that are not served, see Page.Static. With StrictHTML, paths are also checked against
+// the HTML content model, and with Accessibility against the accessibility rules.
 func (p *Page) Validate() map[string]error {
-	errors := make(map[string]error, len(p.Index))
-	for _, path := range p.paths() {
-		e := p.Index[path].Validate()
+	errs := map[string]error{}
+	validated := map[string]bool{}
+	for {
+		pending := []string{}
+		for _, path := range p.paths() {
+			if !validated[path] {
+				validated[path] = true
+				pending = append(pending, path)
+			}
+		}
+		if len(pending) == 0 {
+			break
+		}
+		// Validating initializes Components, which generates ids, so paths are validated in order for the ids to be
+		// the same for every build.
+		for _, path := range pending {
+			e := p.request(path).Validate()
+			if e != nil {
+				errs[path] = e
+			}
+		}
+	}
+	for _, hook := range p.validatedHooks() {
+		e := hook.f()
 		if e != nil {
-			errors[path] = e
+			if errs[hook.path] != nil {
+				e = errors.Join(errs[hook.path], e)
+			}
+			errs[hook.path] = e
 		}
 	}
-	if len(errors) > 0 {
-		return errors
+	// Ids are generated while validating, so they can only be checked once every path is validated.
+	paths := p.paths()
+	logger := p.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	ids := make([]map[string][][]string, len(paths))
+	var mu sync.Mutex
+	p.parallel(len(paths), func(i int) {
+		ids[i] = collectIDs(p.request(paths[i]).Elements)
+	})
+	p.parallel(len(paths), func(i int) {
+		// Requests are swapped into the requests of outer paths, so their ids can be referenced too.
+		available := map[string][][]string{}
+		for path := paths[i]; ; path = gopath.Dir(path) {
+			if j := sort.SearchStrings(paths, path); j < len(paths) && paths[j] == path {
+				for id, occurrences := range ids[j] {
+					available[id] = append(available[id], occurrences...)
+				}
+			}
+			if path == "/" {
+				break
+			}
+		}
+		request := p.request(paths[i])
+		elements := request.Elements
+		found := append(duplicateIDs(ids[i]), danglingReferences(elements, available)...)
+		if request.Endpoint == nil {
+			if raw, e := p.render(request); e == nil {
+				if e = validateAssets(p.Assets, raw); e != nil {
+					found = append(found, e)
+				}
+			}
+		}
+		if p.StrictHTML {
+			if e := element.ValidateHTML(elements); e != nil {
+				found = append(found, e)
+			}
+		}
+		if p.Accessibility != nil {
+			found = append(found, p.Accessibility.check(elements, func(err error) {
+				logger.Warn("accessibility", slog.String("path", paths[i]), slog.String("violation", err.Error()))
+			})...)
+		}
+		e := errors.Join(found...)
+		if e != nil {
+			mu.Lock()
+			if errs[paths[i]] != nil {
+				e = errors.Join(errs[paths[i]], e)
+			}
+			errs[paths[i]] = e
+			mu.Unlock()
+		}
+	})
+	if len(errs) > 0 {
+		return errs
 	}
 	return nil
 }
@@ -52,32 +220,65 @@ func (p *Page) Validate() map[string]error {
 // Render will render all elements in the page to a map of byte slices. Each path is rendered individually, and all
 // paths with data are returned in a map.
 func (p *Page) Render() (map[string]string, error) {
-	t := make(map[string]string, len(p.Index))
-	errs := make([]error, 0, len(p.Index))
-	for name, req := range p.Index {
-		raw, e := req.Render()
+	paths := p.paths()
+	t := make(map[string]string, len(paths))
+	errs := make([]error, 0, len(paths))
+	var mu sync.Mutex
+	p.parallel(len(paths), func(i int) {
+		path := paths[i]
+		req := p.request(path)
+		if req.Endpoint != nil {
+			return
+		}
+		raw, e := p.render(req)
+		mu.Lock()
+		defer mu.Unlock()
 		if e != nil {
 			errs = append(errs, e)
 		}
 		if len(raw) > 0 {
-			t[name] = string(raw)
+			t[path] = string(raw)
 		}
-	}
+	})
 	return t, errors.Join(errs...)
 }
 
 // Build creates a new http.Handler for the entire page.
 func (p *Page) Build() (http.Handler, error) {
+	errorHandler, err := p.buildErrorHandler()
+	if err != nil {
+		return nil, err
+	}
+	errs := p.Validate()
+	for _, path := range p.paths() {
+		if err, ok := errs[path]; ok {
+			return nil, fmt.Errorf("failed to validate request '%s': %w", path, err)
+		}
+	}
+	// Rendering is independent for each path, while the templates are parsed in order into the shared set.
 	paths := p.paths()
+	rendered := make([][]byte, len(paths))
+	renderErrs := make([]error, len(paths))
+	p.parallel(len(paths), func(i int) {
+		if request := p.request(paths[i]); request.Endpoint == nil {
+			rendered[i], renderErrs[i] = p.render(request)
+		}
+	})
 	htmx := http.NewServeMux()
+	endpoints := http.NewServeMux()
 	var page http.Handler
-	for _, path := range paths {
-		request := p.Index[path]
-		err := request.Validate()
-		if err != nil {
-			return nil, fmt.Errorf("failed to validate request '%s': %w", path, err)
+	for i, path := range paths {
+		request := p.request(path)
+		if request.Endpoint != nil {
+			if len(request.Elements) > 0 {
+				return nil, fmt.Errorf("failed to build request '%s': endpoint cannot have elements", path)
+			}
+			handler := p.wrap(path, request.Endpoint)
+			endpoints.Handle(path, handler)
+			request.build(Route{Path: path, Handler: handler})
+			continue
 		}
-		raw, err := request.Render()
+		raw, err := rendered[i], renderErrs[i]
 		if err != nil {
 			return nil, fmt.Errorf("failed to render request '%s': %w", path, err)
 		}
@@ -87,10 +288,17 @@ func (p *Page) Build() (http.Handler, error) {
 			return nil, fmt.Errorf("failed to parse template '%s': %w", path, err)
 		}
 
-		handler := request.Wrap(&TemplateHandler{
-			Template: p.Template,
-			Name:     name,
-		})
+		tmpl := TemplateHandler{
+			Template:     p.Template,
+			Name:         name,
+			ErrorHandler: errorHandler,
+		}
+		if request.Internal {
+			request.build(Route{Path: path, Template: tmpl})
+			continue
+		}
+		handler := p.wrap(path, tmpl)
+		request.build(Route{Path: path, Template: tmpl, Handler: handler})
 		if path == "/" {
 			page = handler
 		} else {
@@ -98,17 +306,59 @@ func (p *Page) Build() (http.Handler, error) {
 		}
 	}
 
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	handler := Recover(p.Logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Endpoints are served regardless of the request type.
+		if _, pattern := endpoints.Handler(r); pattern != "" {
+			endpoints.ServeHTTP(w, r)
+			return
+		}
 		// To create a SPA, we assume any non-HX-Request is a page request.
-		if r.Header.Get("HX-Request") != "true" && page != nil {
+		if !NewHXRequest(r).Partial() && page != nil {
 			page.ServeHTTP(w, r)
 		} else {
 			htmx.ServeHTTP(w, r)
 		}
+	}))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, withErrorHandler(withResponse(w, r), errorHandler))
 	}), nil
 }
 
+// buildErrorHandler resolves the ErrorHandler of the page, building the ErrorComponent if needed.
+func (p *Page) buildErrorHandler() (ErrorHandler, error) {
+	if p.ErrorHandler != nil {
+		return p.ErrorHandler, nil
+	}
+	if p.ErrorComponent == nil {
+		return DefaultErrorHandler, nil
+	}
+	request := Request{Elements: element.Fragment{p.Init(p.ErrorComponent)}}
+	err := request.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate error component: %w", err)
+	}
+	raw, err := request.Render()
+	if err != nil {
+		return nil, fmt.Errorf("failed to render error component: %w", err)
+	}
+	raw = element.Format(raw, p.RenderOptions)
+	name := p.Generator.NewID("template")
+	p.Template, err = p.Template.New(name).Parse(string(raw))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse error component: %w", err)
+	}
+	return ErrorTemplateHandler{
+		Template: TemplateHandler{Template: p.Template, Name: name},
+		Target:   p.ErrorTarget,
+		Swap:     p.ErrorSwap,
+	}.ServeError, nil
+}
+
 func (p *Page) paths() []string {
+	if p.sync != nil {
+		p.sync.index.RLock()
+		defer p.sync.index.RUnlock()
+	}
 	paths := make([]string, 0, len(p.Index))
 	for path := range p.Index {
 		paths = append(paths, path)
@@ -149,6 +399,131 @@ func (p *Page) AtPath(segments ...string) *Page {
 		Generator:  p.Generator,
 		Index:      p.Index,
 		Template:   p.Template,
+
+		ErrorHandler:  p.ErrorHandler,
+		Guards:        p.Guards,
+		HTMX:          p.HTMX,
+		Assets:        p.Assets,
+		HeadSupport:   p.HeadSupport,
+		Concurrency:   p.Concurrency,
+		AnchorWrap:    p.AnchorWrap,
+		RenderOptions: p.RenderOptions,
+		StrictHTML:    p.StrictHTML,
+		Accessibility: p.Accessibility,
+		sync:          p.sync,
+	}
+}
+
+// request returns the request at the path.
+func (p *Page) request(path string) Request {
+	if p.sync != nil {
+		p.sync.index.RLock()
+		defer p.sync.index.RUnlock()
+	}
+	return p.Index[path]
+}
+
+// modify calls f with the request at the path, and stores the modified request.
+func (p *Page) modify(path string, f func(*Request)) {
+	if p.sync != nil {
+		p.sync.index.Lock()
+		defer p.sync.index.Unlock()
+	}
+	request := p.Index[path]
+	f(&request)
+	p.Index[path] = request
+}
+
+// funcs adds the functions to the Template. Components add their functions through this rather than assigning the
+// Template, as Components can be added to copies of the Page concurrently.
+func (p *Page) funcs(m template.FuncMap) {
+	if p.sync != nil {
+		p.sync.template.Lock()
+		defer p.sync.template.Unlock()
+	}
+	p.Template.Funcs(m)
+}
+
+// mount applies a Component that mounts requests when it is validated. It is only applied once, and the result is
+// kept, so validating the Page multiple times does not mount the Component multiple times.
+type mount struct {
+	applied bool
+	err     error
+}
+
+func (m *mount) apply(f func() error) error {
+	if !m.applied {
+		m.applied = true
+		m.err = f()
+	}
+	return m.err
+}
+
+// validatedHook is called once all paths are validated, reporting any error at the path.
+type validatedHook struct {
+	path string
+	f    func() error
+}
+
+// onValidated calls f each time all paths of the Page are validated. This is used by Components that depend on
+// Components anywhere in the Page, as they are only all initialized once validated. f is called for every validation
+// of the Page, so it must be idempotent.
+func (p *Page) onValidated(f func() error) {
+	if p.sync == nil {
+		return
+	}
+	p.sync.index.Lock()
+	defer p.sync.index.Unlock()
+	p.sync.validated = append(p.sync.validated, validatedHook{path: p.Path(), f: f})
+}
+
+func (p *Page) validatedHooks() []validatedHook {
+	if p.sync == nil {
+		return nil
+	}
+	p.sync.index.RLock()
+	defer p.sync.index.RUnlock()
+	return append([]validatedHook{}, p.sync.validated...)
+}
+
+// parallel calls f for each index up to n, with up to Concurrency calls running at once.
+func (p *Page) parallel(n int, f func(i int)) {
+	if p.Concurrency <= 1 {
+		for i := 0; i < n; i++ {
+			f(i)
+		}
+		return
+	}
+	queue := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(p.Concurrency, n); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				f(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+}
+
+// WithGuard returns a new Page at the same path that applies the Guard to every endpoint mounted from it.
+// Resources are shared between both new and old page.
+func (p *Page) WithGuard(g Guard) *Page {
+	page := p.AtPath()
+	page.Guards = append(append([]Guard{}, p.Guards...), g)
+	return page
+}
+
+// useGuards adds the Guards of the Page as middleware at the current path and all paths beneath it.
+func (p *Page) useGuards() {
+	for _, g := range p.Guards {
+		p.UseAll(g.Middleware)
 	}
 }
 
@@ -161,18 +536,49 @@ func (p *Page) Use(middleware ...Middleware) {
 	if p == nil || middleware == nil {
 		return
 	}
-	request := p.Index[p.Path()]
-	request.Middleware = append(request.Middleware, middleware...)
-	p.Index[p.Path()] = request
+	p.modify(p.Path(), func(request *Request) {
+		request.Middleware = append(request.Middleware, middleware...)
+	})
+}
+
+// UseAll adds middleware to the request at this pages current path, and to all requests at paths beneath it. This is
+// used for middleware the whole page depends on, such as Sessions, CSRF and CSP. Middleware of outer paths runs first.
+func (p *Page) UseAll(middleware ...Middleware) {
+	if p == nil || middleware == nil {
+		return
+	}
+	p.modify(p.Path(), func(request *Request) {
+		request.ScopeMiddleware = append(request.ScopeMiddleware, middleware...)
+	})
 }
 
-type Handle func(*http.Request)
+// Handle defines a function called before the template of a request is executed. The returned Data is merged into the
+// template data. A returned error is rendered through the ErrorHandler in place of the template.
+type Handle func(*http.Request) (Data, error)
 
+// Handle adds a Handle to the request at this pages current path.
 func (p *Page) Handle(h Handle) {
 	if p == nil || h == nil {
 		return
 	}
-	p.Use(HandlerMiddleware(h).Middleware)
+	p.modify(p.Path(), func(request *Request) {
+		request.Handles = append(request.Handles, h)
+	})
+}
+
+// wrap wraps the handler in the Handles and Middleware of the request at the path, followed by the ScopeMiddleware
+// of the request and all the requests at the outer paths.
+func (p *Page) wrap(path string, handler http.Handler) http.Handler {
+	handler = p.request(path).Wrap(handler)
+	for {
+		for _, middleware := range p.request(path).ScopeMiddleware {
+			handler = middleware(handler)
+		}
+		if path == "/" {
+			return handler
+		}
+		path = gopath.Dir(path)
+	}
 }
 
 // Add adds a component to the request at this pages current path. This is when a Component is initialized through Init
@@ -181,24 +587,96 @@ func (p *Page) Add(component Component) {
 	if p == nil || component == nil {
 		return
 	}
-	request := p.Index[p.Path()]
-	request.Elements = append(request.Elements, p.Init(component))
-	p.Index[p.Path()] = request
+	// Components can modify the request while being initialized, so it is read after.
+	e := p.Init(component)
+	p.modify(p.Path(), func(request *Request) {
+		request.Elements = append(request.Elements, e)
+	})
+}
+
+// render renders the request, formatted by the RenderOptions. Partial responses include their HeadTags, as there is no
+// Document to render them.
+func (p *Page) render(request Request) ([]byte, error) {
+	if !request.Document && len(request.Head) > 0 {
+		request.Elements = append(element.Fragment{partialHead(request.Head, p.HeadSupport)}, request.Elements...)
+	}
+	raw, err := request.Render()
+	if err != nil {
+		return nil, err
+	}
+	return element.Format(raw, p.RenderOptions), nil
+}
+
+// Endpoint sets the http.Handler to serve at this pages current path. Endpoints are served for every request to the
+// path, not only htmx requests, and cannot have any Components added.
+func (p *Page) Endpoint(h http.Handler) {
+	if p == nil || h == nil {
+		return
+	}
+	p.modify(p.Path(), func(request *Request) {
+		request.Endpoint = h
+	})
+}
+
+// OnBuild adds a function called with the Route built for this pages current path.
+// This allows Components to access the built template of a path.
+func (p *Page) OnBuild(f func(Route)) {
+	if p == nil || f == nil {
+		return
+	}
+	p.modify(p.Path(), func(request *Request) {
+		request.OnBuild = append(request.OnBuild, f)
+	})
 }
 
-type HandlerMiddleware func(*http.Request)
+// HandlerMiddleware adapts a Handle into a Middleware. The result of the Handle is stored in the request context.
+// Once a Handle returns an error, any following Handles are skipped.
+type HandlerMiddleware func(*http.Request) (Data, error)
 
 func (h HandlerMiddleware) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		h(r)
+		r, result := withHandlerResult(r)
+		if result.Err == nil {
+			data, err := h(r)
+			for key, value := range data {
+				result.Data[key] = value
+			}
+			result.Err = err
+		}
 		next.ServeHTTP(w, r)
 	})
 }
 
 // Request defines a single interactive endpoint.
 type Request struct {
-	Elements   element.Fragment
+	Elements element.Fragment
+	// Handles are called in order before the Elements are rendered, with later Handles overriding the Data of earlier
+	// ones. They only apply to this request.
+	Handles []Handle
+	// Middleware wraps this request.
 	Middleware []Middleware
+	// ScopeMiddleware wraps this request, and all requests at paths beneath it.
+	ScopeMiddleware []Middleware
+	// Endpoint is served in place of the Elements if set.
+	Endpoint http.Handler
+	// OnBuild functions are called with the built Route.
+	OnBuild []func(Route)
+	// Head are the tags added to the <head> by Components of this request.
+	Head []HeadTag
+	// Document is set when the request renders a Document, which renders the Head itself.
+	Document bool
+	// Internal requests are built into templates for use by Components, such as the regions of an EventStream, but
+	// are not served.
+	Internal bool
+}
+
+// Route is the result of building a single Request.
+type Route struct {
+	Path string
+	// Template executes the template of the Request. This is empty for Endpoints.
+	Template TemplateHandler
+	// Handler serves the Request, including all Middleware. This is nil for Internal requests.
+	Handler http.Handler
 }
 
 func (r Request) Validate() error {
@@ -207,11 +685,22 @@ func (r Request) Validate() error {
 
 func (r Request) Render() ([]byte, error) {
 	data := bytes.NewBuffer(nil)
-	err := element.Fragment{element.Raw("{{$r := .request}}"), r.Elements}.Render(data)
+	err := element.Fragment{element.Raw("{{$r := .request}}{{$hx := .hx}}"), r.Elements}.Render(data)
 	return data.Bytes(), err
 }
 
+func (r Request) build(route Route) {
+	for _, f := range r.OnBuild {
+		f(route)
+	}
+}
+
+// Wrap wraps the handler in the Handles and Middleware of the request.
 func (r Request) Wrap(handler http.Handler) http.Handler {
+	// Handles are wrapped in reverse so they are called in the order they were added.
+	for i := len(r.Handles) - 1; i >= 0; i-- {
+		handler = HandlerMiddleware(r.Handles[i]).Middleware(handler)
+	}
 	for _, middleware := range r.Middleware {
 		handler = middleware(handler)
 	}
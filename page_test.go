@@ -2,9 +2,15 @@ package gohtmx_test
 
 import (
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
 
 	"github.com/TheWozard/gohtmx"
+	"github.com/TheWozard/gohtmx/attributes"
+	"github.com/TheWozard/gohtmx/element"
 	"github.com/stretchr/testify/require"
 )
 
@@ -35,7 +41,7 @@ func TestPage(t *testing.T) {
 				p.Add(gohtmx.Raw("test"))
 			},
 			rendered: map[string]string{
-				"/": `{{$r := .request}}test`,
+				"/": `{{$r := .request}}{{$hx := .hx}}test`,
 			},
 		},
 		{
@@ -45,7 +51,7 @@ func TestPage(t *testing.T) {
 				p.Add(gohtmx.Raw("test2"))
 			},
 			rendered: map[string]string{
-				"/": `{{$r := .request}}test1test2`,
+				"/": `{{$r := .request}}{{$hx := .hx}}test1test2`,
 			},
 		},
 		{
@@ -54,7 +60,53 @@ func TestPage(t *testing.T) {
 				p.AtPath("example").Add(gohtmx.Raw("test"))
 			},
 			rendered: map[string]string{
-				"/example": `{{$r := .request}}test`,
+				"/example": `{{$r := .request}}{{$hx := .hx}}test`,
+			},
+		},
+		{
+			desc: "duplicate ids",
+			setup: func(p *gohtmx.Page) {
+				p.Add(gohtmx.Div{
+					ID: "a",
+					Content: gohtmx.Fragment{
+						gohtmx.Span{ID: "b"},
+						gohtmx.Span{ID: "a"},
+						gohtmx.Span{ID: "b"},
+					},
+				})
+			},
+			validationErrs: map[string]error{
+				"/": errors.Join(
+					element.PathError{Path: []string{"div", "(0)"}, Err: fmt.Errorf("%w 'a'", gohtmx.ErrDuplicateID)},
+					element.PathError{Path: []string{"div", "(0)", "span", "(1)"}, Err: fmt.Errorf("%w 'a'", gohtmx.ErrDuplicateID)},
+					element.PathError{Path: []string{"div", "(0)", "span", "(0)"}, Err: fmt.Errorf("%w 'b'", gohtmx.ErrDuplicateID)},
+					element.PathError{Path: []string{"div", "(0)", "span", "(2)"}, Err: fmt.Errorf("%w 'b'", gohtmx.ErrDuplicateID)},
+				),
+			},
+			rendered: map[string]string{
+				"/": `{{$r := .request}}{{$hx := .hx}}<div id="a"><span id="b"></span><span id="a"></span><span id="b"></span></div>`,
+			},
+		},
+		{
+			desc: "dangling references",
+			setup: func(p *gohtmx.Page) {
+				p.Add(gohtmx.Div{ID: "a"})
+				p.AtPath("nested").Add(gohtmx.Button{
+					Attr: attributes.New().
+						String("hx-target", "#missing").
+						String("hx-include", "#a, closest form, #b").
+						String("hx-indicator", "this"),
+				})
+			},
+			validationErrs: map[string]error{
+				"/nested": errors.Join(
+					element.PathError{Path: []string{"button", "(0)"}, Err: fmt.Errorf("%w hx-target='#missing'", gohtmx.ErrDanglingReference)},
+					element.PathError{Path: []string{"button", "(0)"}, Err: fmt.Errorf("%w hx-include='#b'", gohtmx.ErrDanglingReference)},
+				),
+			},
+			rendered: map[string]string{
+				"/":       `{{$r := .request}}{{$hx := .hx}}<div id="a"></div>`,
+				"/nested": `{{$r := .request}}{{$hx := .hx}}<button hx-include="#a, closest form, #b" hx-indicator="this" hx-target="#missing" type="button"></button>`,
 			},
 		},
 		{
@@ -66,7 +118,7 @@ func TestPage(t *testing.T) {
 				"/": errors.Join(errors.New("test error")),
 			},
 			rendered: map[string]string{
-				"/": `{{$r := .request}}test error`,
+				"/": `{{$r := .request}}{{$hx := .hx}}test error`,
 			},
 		},
 	}
@@ -74,3 +126,155 @@ func TestPage(t *testing.T) {
 		tC.Assert(t)
 	}
 }
+
+func TestPageConcurrency(t *testing.T) {
+	allowed := gohtmx.Guard{Policy: func(*http.Request) bool { return true }}
+	section := func(page *gohtmx.Page, i int) {
+		interaction := gohtmx.NewInteraction("interaction")
+		page.Add(gohtmx.Guarded{Guard: allowed, Content: gohtmx.Fragment{
+			interaction,
+			interaction.Swap().Update(gohtmx.Div{Content: gohtmx.Raw(fmt.Sprintf("updated %d", i))}),
+			interaction.Trigger().Target(gohtmx.Button{Content: gohtmx.Raw("update")}),
+		}})
+	}
+
+	p := gohtmx.NewPage()
+	p.Concurrency = 8
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			section(p.AtPath(fmt.Sprintf("section_%d", i)), i)
+		}(i)
+	}
+	wg.Wait()
+	h, err := p.Build()
+	require.NoError(t, err)
+
+	for i := 0; i < 50; i++ {
+		r := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/section_%d/interaction", i), nil)
+		r.Header.Set("HX-Request", "true")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		require.Contains(t, w.Body.String(), fmt.Sprintf("updated %d", i))
+	}
+
+	// Components added in the same order render the same regardless of Concurrency.
+	render := func(concurrency int) map[string]string {
+		p := gohtmx.NewPage()
+		p.Concurrency = concurrency
+		for i := 0; i < 50; i++ {
+			section(p.AtPath(fmt.Sprintf("section_%d", i)), i)
+		}
+		require.Nil(t, p.Validate())
+		rendered, err := p.Render()
+		require.NoError(t, err)
+		return rendered
+	}
+	expected := render(1)
+	for i := 0; i < 5; i++ {
+		require.Equal(t, expected, render(8))
+	}
+}
+
+func TestPageStrictHTML(t *testing.T) {
+	p := gohtmx.NewPage()
+	p.Add(gohtmx.P{Content: gohtmx.Div{}})
+	require.Nil(t, p.Validate())
+
+	p.StrictHTML = true
+	errs := p.Validate()
+	require.ErrorIs(t, errs["/"], element.ErrContentModel)
+	require.ErrorContains(t, errs["/"], "p(0).div(0) invalid html: <div> cannot be within <p>")
+}
+
+func TestPageRenderOptions(t *testing.T) {
+	p := gohtmx.NewPage()
+	p.RenderOptions = element.RenderOptions{Mode: element.RenderPretty}
+	p.Add(gohtmx.Div{Content: gohtmx.P{Content: gohtmx.Raw("a")}})
+	rendered, err := p.Render()
+	require.Nil(t, err)
+	require.Equal(t, map[string]string{"/": "{{$r := .request}}{{$hx := .hx}}<div>\n  <p>a</p>\n</div>"}, rendered)
+
+	p.RenderOptions = element.RenderOptions{Mode: element.RenderMinified}
+	rendered, err = p.AtPath("/").Render()
+	require.Nil(t, err)
+	require.Equal(t, map[string]string{"/": "{{$r := .request}}{{$hx := .hx}}<div><p>a</p></div>"}, rendered)
+}
+
+func TestPageHandleOrder(t *testing.T) {
+	p := gohtmx.NewPage()
+	p.ErrorComponent = gohtmx.Raw(`{{.error}}`)
+	called := []string{}
+	p.Handle(func(r *http.Request) (gohtmx.Data, error) {
+		called = append(called, "first")
+		return gohtmx.Data{"value": "first"}, nil
+	})
+	p.Handle(func(r *http.Request) (gohtmx.Data, error) {
+		called = append(called, "second")
+		if r.URL.Query().Has("fail") {
+			return nil, errors.New("second failed")
+		}
+		return gohtmx.Data{"value": "second"}, nil
+	})
+	p.Handle(func(r *http.Request) (gohtmx.Data, error) {
+		called = append(called, "third")
+		return nil, nil
+	})
+	p.Add(gohtmx.Raw(`{{.value}}`))
+	h, err := p.Build()
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, "second", w.Body.String())
+	require.Equal(t, []string{"first", "second", "third"}, called)
+
+	// Handles after an error are skipped.
+	called = []string{}
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?fail", nil))
+	require.Equal(t, "second failed", w.Body.String())
+	require.Equal(t, []string{"first", "second"}, called)
+}
+
+func TestPageUse(t *testing.T) {
+	header := func(name string) gohtmx.Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Add("X-Middleware", name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+	p := gohtmx.NewPage()
+	p.Use(header("use"))
+	p.UseAll(header("root"))
+	p.AtPath("inner").UseAll(header("inner"))
+	p.Add(gohtmx.Raw("root"))
+	p.AtPath("inner").Add(gohtmx.Raw("inner"))
+	p.AtPath("inner", "nested").Add(gohtmx.Raw("nested"))
+	h, err := p.Build()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		path     string
+		expected []string
+	}{
+		{path: "/", expected: []string{"root", "use"}},
+		{path: "/inner", expected: []string{"root", "inner"}},
+		{path: "/inner/nested", expected: []string{"root", "inner"}},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.path, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tC.path, nil)
+			if tC.path != "/" {
+				r.Header.Set("HX-Request", "true")
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			require.Equal(t, tC.expected, w.Header().Values("X-Middleware"))
+		})
+	}
+}
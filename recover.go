@@ -0,0 +1,52 @@
+package gohtmx
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+)
+
+// PanicError is the error rendered when a panic is recovered while serving a request.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+	// Stack is the stack trace of the goroutine that panicked.
+	Stack []byte
+	// Path is the path of the request that panicked.
+	Path string
+}
+
+func (p PanicError) Error() string {
+	return fmt.Sprintf("panic serving %s: %v", p.Path, p.Value)
+}
+
+// Recover creates a Middleware that recovers from panics in the wrapped handler. The panic is logged to the logger
+// and rendered through HandleError. A nil logger uses slog.Default.
+func Recover(logger *slog.Logger) Middleware {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				value := recover()
+				if value == nil {
+					return
+				}
+				// http.ErrAbortHandler is used to intentionally abort a response and is not an error.
+				if value == http.ErrAbortHandler {
+					panic(value)
+				}
+				err := PanicError{Value: value, Stack: debug.Stack(), Path: r.URL.Path}
+				logger.ErrorContext(r.Context(), "recovered from panic",
+					slog.String("path", err.Path),
+					slog.Any("panic", err.Value),
+					slog.String("stack", string(err.Stack)),
+				)
+				HandleError(w, r, err)
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package gohtmx_test
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TheWozard/gohtmx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecover(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		headers  map[string]string
+		expected string
+	}{
+		{
+			desc:     "page request",
+			expected: "<html>boom</html>",
+		},
+		{
+			desc:     "htmx request",
+			headers:  map[string]string{"HX-Request": "true"},
+			expected: "boom",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			logs := bytes.NewBuffer(nil)
+			p := gohtmx.NewPage()
+			p.Logger = slog.New(slog.NewTextHandler(logs, nil))
+			p.ErrorComponent = gohtmx.Raw(`{{if $hx.Request}}{{.error.Value}}{{else}}<html>{{.error.Value}}</html>{{end}}`)
+			p.Handle(func(r *http.Request) (gohtmx.Data, error) {
+				panic("boom")
+			})
+			p.AtPath("partial").Handle(func(r *http.Request) (gohtmx.Data, error) {
+				panic("boom")
+			})
+			h, err := p.Build()
+			require.NoError(t, err)
+
+			r := httptest.NewRequest(http.MethodGet, "/partial", nil)
+			for key, value := range tC.headers {
+				r.Header.Set(key, value)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			require.Equal(t, http.StatusInternalServerError, w.Code)
+			require.Equal(t, tC.expected, w.Body.String())
+			require.Contains(t, logs.String(), "recovered from panic")
+			require.Contains(t, logs.String(), "path=/partial")
+		})
+	}
+}
@@ -1,8 +1,11 @@
 package gohtmx
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"reflect"
+	"strings"
 
 	"github.com/TheWozard/gohtmx/attributes"
 	"github.com/TheWozard/gohtmx/element"
@@ -21,6 +24,8 @@ type Reference struct {
 	// This is stored so any content generated during validation can be added relative to the same location the content
 	// is rendered.
 	Page *Page
+
+	position Position
 }
 
 // -- Component --
@@ -32,6 +37,9 @@ func (m *Reference) Init(p *Page) (element.Element, error) {
 	if m.Initialized == nil {
 		m.Initialized = p.Init(m.Target)
 		m.Page = p
+		if _, ok := p.Generator.(ElementIDGenerator); ok {
+			m.position = p.position(m.Target, m.Initialized)
+		}
 	}
 	return m, nil
 }
@@ -52,6 +60,13 @@ func (m *Reference) Validate() error {
 	return m.Initialized.Validate()
 }
 
+func (m *Reference) Children() []element.Element {
+	if m == nil || m.Initialized == nil {
+		return nil
+	}
+	return []element.Element{m.Initialized}
+}
+
 func (m *Reference) GetTags() []*element.Tag {
 	if m == nil || m.Initialized == nil {
 		return []*element.Tag{}
@@ -72,23 +87,126 @@ func (m *Reference) ID() (string, error) {
 	}
 	id, ok := a.Get("id")
 	if !ok {
-		id = m.Page.Generator.NewID("gohtmx")
+		if g, ok := m.Page.Generator.(ElementIDGenerator); ok {
+			id, err = g.ElementID(m.position)
+			if err != nil {
+				return "", err
+			}
+		} else {
+			id = m.Page.Generator.NewID("gohtmx")
+		}
 		// Delete ensure even if multiple IDs were set, only one is in the final output.
 		a.Delete("id").String("id", id)
 	}
 	return id, nil
 }
 
+// FindAttrs returns the attributes of the tag the initialized Target is referenced by. This is the tag designated by
+// an Anchor within the Target, otherwise the single tag of the Target. Targets with multiple tags are wrapped in a
+// Page.AnchorWrap tag if set.
 func (m *Reference) FindAttrs() (*attributes.Attributes, error) {
 	if m == nil || m.Initialized == nil {
 		return nil, fmt.Errorf(`cannot find attributes of uninitialized Reference`)
 	}
+	if r, ok := m.Initialized.(*Reference); ok {
+		return r.FindAttrs()
+	}
+	tag, err := m.anchor()
+	if err != nil {
+		return nil, err
+	}
+	if tag != nil {
+		tag.Attributes = tag.Attributes.Ensure()
+		return tag.Attributes, nil
+	}
 	tags := m.Initialized.GetTags()
+	if len(tags) != 1 && m.Page != nil && m.Page.AnchorWrap != "" {
+		wrap := &element.Tag{Name: m.Page.AnchorWrap, Attributes: attributes.New(), Content: m.Initialized}
+		m.Initialized = wrap
+		return wrap.Attributes, nil
+	}
 	if len(tags) == 0 {
 		return nil, fmt.Errorf(`failed to get attributes, missing tags in initialized Reference`)
 	}
 	if len(tags) > 1 {
 		return nil, fmt.Errorf(`failed to get attributes, multiple tags in initialized Reference`)
 	}
+	tags[0].Attributes = tags[0].Attributes.Ensure()
 	return tags[0].Attributes, nil
 }
+
+// anchor returns the tag designated by an Anchor within the initialized Target, or nil without an Anchor.
+func (m *Reference) anchor() (*element.Tag, error) {
+	anchors := []anchorElement{}
+	_ = element.Walk(m.Initialized, func(e element.Element, _ []string) error {
+		switch e := e.(type) {
+		case anchorElement:
+			anchors = append(anchors, e)
+			return element.SkipChildren
+		case *Reference:
+			// Anchors of nested References belong to them.
+			return element.SkipChildren
+		}
+		return nil
+	}, nil)
+	if len(anchors) > 1 {
+		return nil, fmt.Errorf(`failed to get attributes, multiple anchors in initialized Reference`)
+	}
+	if len(anchors) == 0 {
+		return nil, nil
+	}
+	return anchors[0].tag()
+}
+
+// outer returns the element replaced by an outerHTML swap of the Reference. With an Anchor only the anchored tag is
+// replaced, so the other tags of the Target are not swapped in again.
+func (m *Reference) outer() (element.Element, error) {
+	if m == nil || m.Initialized == nil {
+		return nil, fmt.Errorf(`cannot swap uninitialized Reference`)
+	}
+	if r, ok := m.Initialized.(*Reference); ok {
+		return r.outer()
+	}
+	tag, err := m.anchor()
+	if err != nil || tag == nil {
+		return m, err
+	}
+	return tag, nil
+}
+
+// Query returns the tags of the initialized Target matching the CSS selector. See element.CompileSelector.
+func (m *Reference) Query(selector string) ([]*element.Tag, error) {
+	if m == nil || m.Initialized == nil {
+		return nil, fmt.Errorf(`cannot query uninitialized Reference`)
+	}
+	return element.Query(m.Initialized, selector)
+}
+
+// position returns the Position of the Component initialized at the current path.
+func (p *Page) position(c Component, e element.Element) Position {
+	t := reflect.TypeOf(c)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	position := Position{Path: p.Path(), Type: strings.ToLower(t.Name())}
+	if position.Type == "" {
+		position.Type = "component"
+	}
+	if p.sync != nil {
+		p.sync.index.Lock()
+		key := position.Path + " " + position.Type
+		if p.sync.positions == nil {
+			p.sync.positions = map[string]int{}
+		}
+		position.Index = p.sync.positions[key]
+		p.sync.positions[key]++
+		p.sync.index.Unlock()
+	}
+	content := bytes.NewBuffer(nil)
+	if e != nil {
+		// Content that fails to render is reported by validation.
+		_ = e.Render(content)
+	}
+	position.Hash = contentHash(content.Bytes())
+	return position
+}
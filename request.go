@@ -0,0 +1,57 @@
+package gohtmx
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// HXRequest is a parsed view of the htmx request headers. See https://htmx.org/reference/#request_headers
+type HXRequest struct {
+	// Request is true for any request made by htmx.
+	Request bool
+	// Boosted is true if the request was made through an element using hx-boost.
+	Boosted bool
+	// CurrentURL is the current url of the browser.
+	CurrentURL string
+	// HistoryRestoreRequest is true if the request is for history restoration after a miss in the local history cache.
+	HistoryRestoreRequest bool
+	// Prompt is the user response to an hx-prompt.
+	Prompt string
+	// Target is the id of the target element if it exists.
+	Target string
+	// Trigger is the id of the triggered element if it exists.
+	Trigger string
+	// TriggerName is the name of the triggered element if it exists.
+	TriggerName string
+	// Socket is true if the request was dispatched from a message sent over a Socket.
+	Socket bool
+}
+
+// NewHXRequest parses the htmx request headers of the passed request.
+func NewHXRequest(r *http.Request) HXRequest {
+	if r == nil {
+		return HXRequest{}
+	}
+	return HXRequest{
+		Request:               r.Header.Get("HX-Request") == "true",
+		Boosted:               r.Header.Get("HX-Boosted") == "true",
+		CurrentURL:            r.Header.Get("HX-Current-URL"),
+		HistoryRestoreRequest: r.Header.Get("HX-History-Restore-Request") == "true",
+		Prompt:                r.Header.Get("HX-Prompt"),
+		Target:                r.Header.Get("HX-Target"),
+		Trigger:               r.Header.Get("HX-Trigger"),
+		TriggerName:           r.Header.Get("HX-Trigger-Name"),
+		Socket:                r.Context().Value(socketKey{}) != nil,
+	}
+}
+
+// Partial returns true if the request expects only a fragment of the page to be returned.
+// History restoration requests expect a full page even though they are made by htmx.
+func (h HXRequest) Partial() bool {
+	return h.Request && !h.HistoryRestoreRequest
+}
+
+// URL parses the CurrentURL.
+func (h HXRequest) URL() (*url.URL, error) {
+	return url.Parse(h.CurrentURL)
+}
@@ -0,0 +1,99 @@
+package gohtmx_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TheWozard/gohtmx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHXRequest(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		headers  map[string]string
+		expected gohtmx.HXRequest
+	}{
+		{
+			desc:     "non htmx request",
+			expected: gohtmx.HXRequest{},
+		},
+		{
+			desc: "all headers",
+			headers: map[string]string{
+				"HX-Request":                 "true",
+				"HX-Boosted":                 "true",
+				"HX-Current-URL":             "http://localhost/?a=b",
+				"HX-History-Restore-Request": "true",
+				"HX-Prompt":                  "prompt",
+				"HX-Target":                  "target",
+				"HX-Trigger":                 "trigger",
+				"HX-Trigger-Name":            "name",
+			},
+			expected: gohtmx.HXRequest{
+				Request:               true,
+				Boosted:               true,
+				CurrentURL:            "http://localhost/?a=b",
+				HistoryRestoreRequest: true,
+				Prompt:                "prompt",
+				Target:                "target",
+				Trigger:               "trigger",
+				TriggerName:           "name",
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			for key, value := range tC.headers {
+				r.Header.Set(key, value)
+			}
+			require.Equal(t, tC.expected, gohtmx.NewHXRequest(r))
+		})
+	}
+}
+
+func TestHXRequestTemplate(t *testing.T) {
+	p := gohtmx.NewPage()
+	p.Add(gohtmx.Raw(`{{if $hx.HistoryRestoreRequest}}restore{{else}}full{{end}}`))
+	p.AtPath("partial").Add(gohtmx.Raw(`{{$hx.Target}}`))
+	h, err := p.Build()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc     string
+		path     string
+		headers  map[string]string
+		expected string
+	}{
+		{
+			desc:     "page request",
+			path:     "/",
+			expected: "full",
+		},
+		{
+			desc:     "history restore request",
+			path:     "/partial",
+			headers:  map[string]string{"HX-Request": "true", "HX-History-Restore-Request": "true"},
+			expected: "restore",
+		},
+		{
+			desc:     "partial request",
+			path:     "/partial",
+			headers:  map[string]string{"HX-Request": "true", "HX-Target": "target"},
+			expected: "target",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tC.path, nil)
+			for key, value := range tC.headers {
+				r.Header.Set(key, value)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			require.Equal(t, tC.expected, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package gohtmx
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// Response provides typed access to the htmx response headers. See https://htmx.org/reference/#response_headers
+// Headers must be set before the response body is written.
+type Response struct {
+	Header http.Header
+}
+
+// NewResponse creates a Response that writes to the headers of the passed http.ResponseWriter.
+func NewResponse(w http.ResponseWriter) Response {
+	return Response{Header: w.Header()}
+}
+
+type responseKey struct{}
+
+// ResponseFromRequest returns the Response for the passed request. This is used by handlers that only have access to
+// the *http.Request. If the request was not served through a Page, the returned Response discards all headers.
+func ResponseFromRequest(r *http.Request) Response {
+	if r != nil {
+		if header, ok := r.Context().Value(responseKey{}).(http.Header); ok {
+			return Response{Header: header}
+		}
+	}
+	return Response{Header: http.Header{}}
+}
+
+// withResponse stores the response headers of w in the context of r so they are accessible to handlers.
+func withResponse(w http.ResponseWriter, r *http.Request) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), responseKey{}, w.Header()))
+}
+
+// Trigger triggers the named event on the client as soon as the response is received.
+// The detail is encoded as JSON and passed as the event detail, nil sends the event without detail.
+func (r Response) Trigger(event string, detail any) error {
+	return r.trigger("HX-Trigger", event, detail)
+}
+
+// TriggerAfterSwap triggers the named event on the client after the swap step.
+func (r Response) TriggerAfterSwap(event string, detail any) error {
+	return r.trigger("HX-Trigger-After-Swap", event, detail)
+}
+
+// TriggerAfterSettle triggers the named event on the client after the settle step.
+func (r Response) TriggerAfterSettle(event string, detail any) error {
+	return r.trigger("HX-Trigger-After-Settle", event, detail)
+}
+
+// trigger merges the event into the JSON object form of the header so multiple events can be triggered. A header that
+// is not JSON is read as the comma separated event names htmx also accepts.
+func (r Response) trigger(header, event string, detail any) error {
+	events := map[string]json.RawMessage{}
+	if current := r.Header.Get(header); current != "" {
+		err := json.Unmarshal([]byte(current), &events)
+		if err != nil {
+			events = map[string]json.RawMessage{}
+			for _, name := range strings.Split(current, ",") {
+				if name = strings.TrimSpace(name); name != "" {
+					events[name] = json.RawMessage("null")
+				}
+			}
+		}
+	}
+	raw, err := json.Marshal(detail)
+	if err != nil {
+		return err
+	}
+	events[event] = raw
+	value, err := json.Marshal(events)
+	if err != nil {
+		return err
+	}
+	r.Header.Set(header, string(value))
+	return nil
+}
+
+// Redirect performs a client side redirect that does a full page reload.
+func (r Response) Redirect(url string) Response {
+	r.Header.Set("HX-Redirect", url)
+	return r
+}
+
+// Refresh performs a full refresh of the page on the client.
+func (r Response) Refresh() Response {
+	r.Header.Set("HX-Refresh", "true")
+	return r
+}
+
+// PushURL pushes a new url into the history stack.
+func (r Response) PushURL(url string) Response {
+	r.Header.Set("HX-Push-Url", url)
+	return r
+}
+
+// ReplaceURL replaces the current url in the location bar.
+func (r Response) ReplaceURL(url string) Response {
+	r.Header.Set("HX-Replace-Url", url)
+	return r
+}
+
+// Retarget sets the CSS selector of the element the response is swapped into.
+func (r Response) Retarget(selector string) Response {
+	r.Header.Set("HX-Retarget", selector)
+	return r
+}
+
+// Reswap sets the SwapMethod used to swap the response.
+func (r Response) Reswap(method SwapMethod) Response {
+	r.Header.Set("HX-Reswap", string(method))
+	return r
+}
+
+// Reselect sets the CSS selector of the part of the response that is swapped in.
+func (r Response) Reselect(selector string) Response {
+	r.Header.Set("HX-Reselect", selector)
+	return r
+}
+
+// Location defines a client side redirect that does not do a full page reload. See https://htmx.org/headers/hx-location/
+type Location struct {
+	Path    string            `json:"path"`
+	Source  string            `json:"source,omitempty"`
+	Event   string            `json:"event,omitempty"`
+	Handler string            `json:"handler,omitempty"`
+	Target  string            `json:"target,omitempty"`
+	Swap    SwapMethod        `json:"swap,omitempty"`
+	Values  map[string]any    `json:"values,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Select  string            `json:"select,omitempty"`
+}
+
+// Location performs a client side redirect to the Location without a full page reload.
+// If only the Path is set, the plain path is used as the header value.
+func (r Response) Location(l Location) error {
+	if l.Source == "" && l.Event == "" && l.Handler == "" && l.Target == "" && l.Swap == "" &&
+		len(l.Values) == 0 && len(l.Headers) == 0 && l.Select == "" {
+		r.Header.Set("HX-Location", l.Path)
+		return nil
+	}
+	raw, err := json.Marshal(l)
+	if err != nil {
+		return err
+	}
+	r.Header.Set("HX-Location", string(raw))
+	return nil
+}
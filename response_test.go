@@ -0,0 +1,105 @@
+package gohtmx_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TheWozard/gohtmx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponse(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		apply    func(r gohtmx.Response) error
+		expected http.Header
+	}{
+		{
+			desc: "trigger without detail",
+			apply: func(r gohtmx.Response) error {
+				return r.Trigger("event", nil)
+			},
+			expected: http.Header{"Hx-Trigger": {`{"event":null}`}},
+		},
+		{
+			desc: "multiple triggers with detail",
+			apply: func(r gohtmx.Response) error {
+				err := r.Trigger("a", "message")
+				if err != nil {
+					return err
+				}
+				return r.Trigger("b", map[string]int{"count": 1})
+			},
+			expected: http.Header{"Hx-Trigger": {`{"a":"message","b":{"count":1}}`}},
+		},
+		{
+			desc: "trigger merges plain event names",
+			apply: func(r gohtmx.Response) error {
+				r.Header.Set("HX-Trigger", "refresh, reload")
+				return r.Trigger("event", 1)
+			},
+			expected: http.Header{"Hx-Trigger": {`{"event":1,"refresh":null,"reload":null}`}},
+		},
+		{
+			desc: "trigger after swap and settle",
+			apply: func(r gohtmx.Response) error {
+				err := r.TriggerAfterSwap("swapped", nil)
+				if err != nil {
+					return err
+				}
+				return r.TriggerAfterSettle("settled", nil)
+			},
+			expected: http.Header{
+				"Hx-Trigger-After-Swap":   {`{"swapped":null}`},
+				"Hx-Trigger-After-Settle": {`{"settled":null}`},
+			},
+		},
+		{
+			desc: "swap headers",
+			apply: func(r gohtmx.Response) error {
+				r.Retarget("#target").Reswap(gohtmx.SwapBeforeEnd).Reselect(".content")
+				return nil
+			},
+			expected: http.Header{
+				"Hx-Retarget": {"#target"},
+				"Hx-Reswap":   {"beforeend"},
+				"Hx-Reselect": {".content"},
+			},
+		},
+		{
+			desc: "navigation headers",
+			apply: func(r gohtmx.Response) error {
+				r.Redirect("/redirect").Refresh().PushURL("/push").ReplaceURL("/replace")
+				return nil
+			},
+			expected: http.Header{
+				"Hx-Redirect":    {"/redirect"},
+				"Hx-Refresh":     {"true"},
+				"Hx-Push-Url":    {"/push"},
+				"Hx-Replace-Url": {"/replace"},
+			},
+		},
+		{
+			desc: "location path",
+			apply: func(r gohtmx.Response) error {
+				return r.Location(gohtmx.Location{Path: "/path"})
+			},
+			expected: http.Header{"Hx-Location": {"/path"}},
+		},
+		{
+			desc: "location with context",
+			apply: func(r gohtmx.Response) error {
+				return r.Location(gohtmx.Location{Path: "/path", Target: "#target"})
+			},
+			expected: http.Header{"Hx-Location": {`{"path":"/path","target":"#target"}`}},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			require.NoError(t, tC.apply(gohtmx.NewResponse(w)))
+			require.Equal(t, tC.expected, w.Header())
+		})
+	}
+}
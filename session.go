@@ -0,0 +1,416 @@
+package gohtmx
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrSessionNotFound is returned by a SessionStore when no session exists for an id.
+var ErrSessionNotFound = errors.New("session not found")
+
+// SessionData is the stored state of a single session. Values are stored as JSON so they can be decoded into their
+// original type regardless of the SessionStore.
+type SessionData struct {
+	ID      string                     `json:"id"`
+	Values  map[string]json.RawMessage `json:"values"`
+	Created time.Time                  `json:"created"`
+	Expires time.Time                  `json:"expires"`
+}
+
+// clone copies the data so the Values are not shared with the copy.
+func (d SessionData) clone() SessionData {
+	values := make(map[string]json.RawMessage, len(d.Values))
+	for key, value := range d.Values {
+		values[key] = append(json.RawMessage{}, value...)
+	}
+	d.Values = values
+	return d
+}
+
+// SessionStore persists sessions between requests.
+type SessionStore interface {
+	// Load returns the session with the id, or ErrSessionNotFound.
+	Load(id string) (SessionData, error)
+	// Save creates or replaces the session.
+	Save(data SessionData) error
+	// Delete removes the session with the id. Deleting a missing session is not an error.
+	Delete(id string) error
+}
+
+// NewMemorySessionStore creates a new SessionStore that keeps sessions in memory.
+func NewMemorySessionStore() *MemorySessionStore {
+	return &MemorySessionStore{sessions: map[string]SessionData{}}
+}
+
+// MemorySessionStore keeps sessions in memory. Sessions are copied in and out of the store, so changes to a loaded
+// session are only kept once it is saved. Sessions are lost when the process exits. Expired sessions are pruned
+// while saving, at most once every PruneInterval.
+type MemorySessionStore struct {
+	mu       sync.RWMutex
+	sessions map[string]SessionData
+	pruned   time.Time
+}
+
+// PruneInterval is the minimum time between pruning expired sessions from a MemorySessionStore while saving.
+const PruneInterval = time.Minute
+
+func (m *MemorySessionStore) Load(id string) (SessionData, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	data, ok := m.sessions[id]
+	if !ok {
+		return SessionData{}, ErrSessionNotFound
+	}
+	return data.clone(), nil
+}
+
+func (m *MemorySessionStore) Save(data SessionData) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if now := time.Now(); now.Sub(m.pruned) >= PruneInterval {
+		m.pruneLocked(now)
+	}
+	m.sessions[data.ID] = data.clone()
+	return nil
+}
+
+func (m *MemorySessionStore) Delete(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, id)
+	return nil
+}
+
+// Prune removes all expired sessions.
+func (m *MemorySessionStore) Prune() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pruneLocked(time.Now())
+}
+
+func (m *MemorySessionStore) pruneLocked(now time.Time) {
+	m.pruned = now
+	for id, data := range m.sessions {
+		if now.After(data.Expires) {
+			delete(m.sessions, id)
+		}
+	}
+}
+
+// FileSessionStore keeps each session as a JSON file in the directory.
+type FileSessionStore struct {
+	Dir string
+}
+
+func (f FileSessionStore) Load(id string) (SessionData, error) {
+	path, err := f.path(id)
+	if err != nil {
+		return SessionData{}, err
+	}
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return SessionData{}, ErrSessionNotFound
+	}
+	if err != nil {
+		return SessionData{}, err
+	}
+	data := SessionData{}
+	err = json.Unmarshal(raw, &data)
+	return data, err
+}
+
+func (f FileSessionStore) Save(data SessionData) error {
+	path, err := f.path(data.ID)
+	if err != nil {
+		return err
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	// Write to a temporary file first so a concurrent Load never reads a partial session.
+	tmp, err := os.CreateTemp(f.Dir, ".session-*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(raw)
+	if err == nil {
+		err = tmp.Close()
+	} else {
+		_ = tmp.Close()
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+	return err
+}
+
+func (f FileSessionStore) Delete(id string) error {
+	path, err := f.path(id)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// path returns the file of the session. Ids come from cookies, so they are checked to stay within the directory.
+func (f FileSessionStore) path(id string) (string, error) {
+	if id == "" || strings.ContainsAny(id, `/\.`) {
+		return "", fmt.Errorf("invalid session id")
+	}
+	return filepath.Join(f.Dir, id+".json"), nil
+}
+
+// SessionOptions configures the Sessions Middleware.
+type SessionOptions struct {
+	// Store persists the sessions. Defaults to a MemorySessionStore.
+	Store SessionStore
+	// CookieName is the name of the cookie holding the session id. Defaults to "gohtmx_session".
+	CookieName string
+	// MaxAge is how long a session lives without requests. Defaults to 24 hours.
+	MaxAge time.Duration
+	// RotateAfter is how long a session id is used before it is replaced. Zero disables rotation.
+	RotateAfter time.Duration
+	// Secure restricts the cookie to https.
+	Secure bool
+}
+
+// Sessions creates a Middleware that loads the session of the client into the request.
+// Expired sessions are replaced by new ones, and session ids older than RotateAfter are rotated. New sessions are only
+// stored, and their cookie only set, once a value is Set, so clients that never use the session do not fill the Store.
+// GetSession only returns the session for requests beneath the path it is added at through UseAll.
+func Sessions(options SessionOptions) Middleware {
+	if options.Store == nil {
+		options.Store = NewMemorySessionStore()
+	}
+	if options.CookieName == "" {
+		options.CookieName = "gohtmx_session"
+	}
+	if options.MaxAge == 0 {
+		options.MaxAge = 24 * time.Hour
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// An outer Sessions already loaded the session, loading it again would save it twice.
+			if GetSession(r) != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			session, err := loadSession(r, options)
+			if err != nil {
+				HandleError(w, r, err)
+				return
+			}
+			session.header = w.Header()
+			if session.stored {
+				session.setCookie()
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), sessionKey{}, session)))
+			// The response has been written, so failing to save can only be logged by the store.
+			_ = session.save()
+		})
+	}
+}
+
+func loadSession(r *http.Request, options SessionOptions) (*Session, error) {
+	session := &Session{options: options}
+	if cookie, err := r.Cookie(options.CookieName); err == nil {
+		data, err := options.Store.Load(cookie.Value)
+		if err != nil && !errors.Is(err, ErrSessionNotFound) {
+			return nil, err
+		}
+		if err == nil && time.Now().Before(data.Expires) {
+			session.data = data
+			session.stored = true
+		} else if err == nil {
+			_ = options.Store.Delete(data.ID)
+		}
+	}
+	if session.data.ID == "" {
+		return session, session.reset()
+	}
+	if options.RotateAfter > 0 && time.Since(session.data.Created) > options.RotateAfter {
+		return session, session.Rotate()
+	}
+	return session, nil
+}
+
+type sessionKey struct{}
+
+// GetSession returns the Session of the request, or nil if the request is not wrapped by Sessions.
+func GetSession(r *http.Request) *Session {
+	if r == nil {
+		return nil
+	}
+	session, _ := r.Context().Value(sessionKey{}).(*Session)
+	return session
+}
+
+// SessionValue returns the value stored in the session of the request under the key, decoded as T.
+func SessionValue[T any](r *http.Request, key string) (T, bool) {
+	var value T
+	session := GetSession(r)
+	if session == nil {
+		return value, false
+	}
+	ok, err := session.Get(key, &value)
+	return value, ok && err == nil
+}
+
+// Session is the state of a single client. Session is safe for concurrent use.
+type Session struct {
+	mu      sync.Mutex
+	data    SessionData
+	rotated []string
+	options SessionOptions
+	header  http.Header
+	// stored is set once the session is kept in the Store, either loaded from it or after a value is Set.
+	stored bool
+}
+
+// ID returns the current id of the session.
+func (s *Session) ID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.ID
+}
+
+// Get decodes the value stored under the key into v. Returns false if there is no value for the key.
+func (s *Session) Get(key string, v any) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	raw, ok := s.data.Values[key]
+	if !ok {
+		return false, nil
+	}
+	return true, json.Unmarshal(raw, v)
+}
+
+// Set stores the value under the key. The value must be encodable as JSON.
+func (s *Session) Set(key string, value any) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Values[key] = raw
+	if !s.stored {
+		s.stored = true
+		if s.header != nil {
+			s.setCookieLocked()
+		}
+	}
+	return nil
+}
+
+// Delete removes the value stored under the key.
+func (s *Session) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data.Values, key)
+}
+
+// Rotate replaces the id of the session while keeping its values. This should be called whenever the privileges of
+// the client change, such as logging in, and must be called before the response is written.
+func (s *Session) Rotate() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id, err := newSessionID()
+	if err != nil {
+		return err
+	}
+	if s.data.ID != "" {
+		s.rotated = append(s.rotated, s.data.ID)
+	}
+	s.data.ID = id
+	s.data.Created = time.Now()
+	if s.header != nil && s.stored {
+		s.setCookieLocked()
+	}
+	return nil
+}
+
+// Clear removes all values from the session and rotates its id. This should be used when logging out.
+func (s *Session) Clear() error {
+	s.mu.Lock()
+	s.data.Values = map[string]json.RawMessage{}
+	s.mu.Unlock()
+	return s.Rotate()
+}
+
+func (s *Session) reset() error {
+	s.data = SessionData{Values: map[string]json.RawMessage{}}
+	return s.Rotate()
+}
+
+func (s *Session) save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range s.rotated {
+		err := s.options.Store.Delete(id)
+		if err != nil {
+			return err
+		}
+	}
+	s.rotated = nil
+	if !s.stored {
+		return nil
+	}
+	return s.options.Store.Save(s.data)
+}
+
+func (s *Session) setCookie() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.setCookieLocked()
+}
+
+// setCookieLocked sets the session cookie on the response, replacing any session cookie already set.
+func (s *Session) setCookieLocked() {
+	s.data.Expires = time.Now().Add(s.options.MaxAge)
+	cookies := s.header.Values("Set-Cookie")
+	s.header.Del("Set-Cookie")
+	for _, cookie := range cookies {
+		if !strings.HasPrefix(cookie, s.options.CookieName+"=") {
+			s.header.Add("Set-Cookie", cookie)
+		}
+	}
+	cookie := &http.Cookie{
+		Name:     s.options.CookieName,
+		Value:    s.data.ID,
+		Path:     "/",
+		Expires:  s.data.Expires,
+		MaxAge:   int(s.options.MaxAge.Seconds()),
+		HttpOnly: true,
+		Secure:   s.options.Secure,
+		SameSite: http.SameSiteLaxMode,
+	}
+	s.header.Add("Set-Cookie", cookie.String())
+}
+
+func newSessionID() (string, error) {
+	raw := make([]byte, 32)
+	_, err := rand.Read(raw)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
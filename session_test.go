@@ -0,0 +1,193 @@
+package gohtmx_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TheWozard/gohtmx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessions(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		store gohtmx.SessionStore
+	}{
+		{
+			desc:  "memory store",
+			store: gohtmx.NewMemorySessionStore(),
+		},
+		{
+			desc:  "file store",
+			store: gohtmx.FileSessionStore{Dir: t.TempDir()},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			p := gohtmx.NewPage()
+			p.UseAll(gohtmx.Sessions(gohtmx.SessionOptions{Store: tC.store}))
+			p.Add(gohtmx.Raw(`{{session $r "count"}}`))
+			interaction := gohtmx.NewInteraction("increment").Handle(func(r *http.Request) (gohtmx.Data, error) {
+				count, _ := gohtmx.SessionValue[int](r, "count")
+				return nil, gohtmx.GetSession(r).Set("count", count+1)
+			})
+			p.Add(gohtmx.Fragment{
+				interaction,
+				interaction.Trigger().Target(gohtmx.Button{}),
+			})
+			h, err := p.Build()
+			require.NoError(t, err)
+
+			serve := func(method, path string, cookies []*http.Cookie) *httptest.ResponseRecorder {
+				r := httptest.NewRequest(method, path, nil)
+				for _, cookie := range cookies {
+					r.AddCookie(cookie)
+				}
+				if path != "/" {
+					r.Header.Set("HX-Request", "true")
+				}
+				w := httptest.NewRecorder()
+				h.ServeHTTP(w, r)
+				return w
+			}
+
+			// Sessions without values are not stored, so no cookie is set.
+			w := serve(http.MethodGet, "/", nil)
+			require.Empty(t, w.Result().Cookies())
+			require.Equal(t, `<button hx-post="/increment" hx-swap="none" type="button"></button>`, w.Body.String())
+
+			w = serve(http.MethodPost, "/increment", nil)
+			cookies := w.Result().Cookies()
+			require.Len(t, cookies, 1)
+			require.Equal(t, "gohtmx_session", cookies[0].Name)
+			serve(http.MethodPost, "/increment", cookies)
+			w = serve(http.MethodGet, "/", cookies)
+			require.Equal(t, `2<button hx-post="/increment" hx-swap="none" type="button"></button>`, w.Body.String())
+			require.Equal(t, cookies[0].Value, w.Result().Cookies()[0].Value)
+
+			data, err := tC.store.Load(cookies[0].Value)
+			require.NoError(t, err)
+			require.JSONEq(t, `2`, string(data.Values["count"]))
+		})
+	}
+}
+
+func TestSessionsRotation(t *testing.T) {
+	store := gohtmx.NewMemorySessionStore()
+	p := gohtmx.NewPage()
+	p.UseAll(gohtmx.Sessions(gohtmx.SessionOptions{Store: store, RotateAfter: time.Nanosecond}))
+	p.Handle(func(r *http.Request) (gohtmx.Data, error) {
+		return nil, gohtmx.GetSession(r).Set("key", "value")
+	})
+	h, err := p.Build()
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	first := w.Result().Cookies()[0]
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(first)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	second := w.Result().Cookies()[0]
+	require.NotEqual(t, first.Value, second.Value)
+
+	_, err = store.Load(first.Value)
+	require.ErrorIs(t, err, gohtmx.ErrSessionNotFound)
+	data, err := store.Load(second.Value)
+	require.NoError(t, err)
+	require.JSONEq(t, `"value"`, string(data.Values["key"]))
+}
+
+// countingStore counts the sessions saved to the store.
+type countingStore struct {
+	gohtmx.SessionStore
+	saved int
+}
+
+func (c *countingStore) Save(data gohtmx.SessionData) error {
+	c.saved++
+	return c.SessionStore.Save(data)
+}
+
+func TestSessionsUnused(t *testing.T) {
+	store := &countingStore{SessionStore: gohtmx.NewMemorySessionStore()}
+	p := gohtmx.NewPage()
+	p.UseAll(gohtmx.Sessions(gohtmx.SessionOptions{Store: store}))
+	p.Add(gohtmx.Raw(`{{session $r "key"}}`))
+	h, err := p.Build()
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		require.Empty(t, w.Result().Cookies())
+	}
+	require.Equal(t, 0, store.saved)
+}
+
+func TestMemorySessionStorePrune(t *testing.T) {
+	store := gohtmx.NewMemorySessionStore()
+	require.NoError(t, store.Save(gohtmx.SessionData{ID: "expired", Expires: time.Now().Add(-time.Minute)}))
+	require.NoError(t, store.Save(gohtmx.SessionData{ID: "active", Expires: time.Now().Add(time.Minute)}))
+	store.Prune()
+	_, err := store.Load("expired")
+	require.ErrorIs(t, err, gohtmx.ErrSessionNotFound)
+	_, err = store.Load("active")
+	require.NoError(t, err)
+}
+
+func TestSessionsConcurrent(t *testing.T) {
+	store := gohtmx.NewMemorySessionStore()
+	p := gohtmx.NewPage()
+	p.UseAll(gohtmx.Sessions(gohtmx.SessionOptions{Store: store}))
+	// Every concurrent request has loaded the session before any sets a value.
+	var loaded sync.WaitGroup
+	loaded.Add(10)
+	interaction := gohtmx.NewInteraction("set").Handle(func(r *http.Request) (gohtmx.Data, error) {
+		if r.FormValue("key") != "initial" {
+			loaded.Done()
+			loaded.Wait()
+		}
+		return nil, gohtmx.GetSession(r).Set(r.FormValue("key"), true)
+	})
+	p.Add(gohtmx.Fragment{
+		interaction,
+		interaction.Trigger().Target(gohtmx.Button{}),
+	})
+	h, err := p.Build()
+	require.NoError(t, err)
+
+	serve := func(key string, cookies []*http.Cookie) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/set?key="+key, nil)
+		r.Header.Set("HX-Request", "true")
+		for _, cookie := range cookies {
+			r.AddCookie(cookie)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+	cookies := serve("initial", nil).Result().Cookies()
+	require.Len(t, cookies, 1)
+
+	// Requests of the same session each change their own copy of the values.
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			serve(fmt.Sprintf("key_%d", i), cookies)
+		}(i)
+	}
+	wg.Wait()
+	data, err := store.Load(cookies[0].Value)
+	require.NoError(t, err)
+	require.Len(t, data.Values, 2)
+	require.Contains(t, data.Values, "initial")
+}
@@ -0,0 +1,81 @@
+package gohtmx
+
+import (
+	"html/template"
+	"sync"
+
+	"github.com/TheWozard/gohtmx/element"
+)
+
+// Observable defines a value that Components can depend on.
+type Observable interface {
+	// Dependents returns the References of all Components that depend on the value.
+	Dependents() []*Reference
+}
+
+// NewSignal creates a new Signal with the initial value.
+func NewSignal[T any](value T) *Signal[T] {
+	return &Signal[T]{value: value}
+}
+
+// Signal defines a reactive value shared by the whole page. Components read the value through Read, and any
+// Interaction that Writes the Signal will automatically swap every Component reading it.
+// Signal is safe for concurrent use.
+type Signal[T any] struct {
+	mu         sync.RWMutex
+	value      T
+	dependents []*Reference
+}
+
+// Get returns the current value.
+func (s *Signal[T]) Get() T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.value
+}
+
+// Set updates the current value. Set is expected to be called from the Handle of an Interaction that Writes the
+// Signal, so the dependent Components are swapped with the new value.
+func (s *Signal[T]) Set(value T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.value = value
+}
+
+// Read renders the Component with . set to the value of the Signal. The Component must render a single tag so it can
+// be targeted when the Signal changes.
+func (s *Signal[T]) Read(c Component) Component {
+	if s == nil || c == nil {
+		return nil
+	}
+	ref := &Reference{Target: signalRead[T]{signal: s, content: c}}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dependents = append(s.dependents, ref)
+	return ref
+}
+
+// Dependents returns the References of all Components reading the Signal.
+func (s *Signal[T]) Dependents() []*Reference {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]*Reference{}, s.dependents...)
+}
+
+type signalRead[T any] struct {
+	signal  *Signal[T]
+	content Component
+}
+
+func (r signalRead[T]) Init(p *Page) (element.Element, error) {
+	id := p.Generator.NewID("signal")
+	// A single item range is used over with, so the content is still rendered for zero values.
+	p.funcs(template.FuncMap{id: func() []T {
+		return []T{r.signal.Get()}
+	}})
+	return element.TBlock{
+		Text:       "range " + id,
+		IncludeEnd: true,
+		Element:    p.Init(r.content),
+	}, nil
+}
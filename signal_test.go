@@ -0,0 +1,89 @@
+package gohtmx_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TheWozard/gohtmx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignal(t *testing.T) {
+	p := gohtmx.NewPage()
+	count := gohtmx.NewSignal(0)
+	interaction := gohtmx.NewInteraction("increment").Writes(count).Handle(func(r *http.Request) (gohtmx.Data, error) {
+		count.Set(count.Get() + 1)
+		return nil, nil
+	})
+	p.Add(gohtmx.Fragment{
+		interaction,
+		count.Read(gohtmx.Span{Content: gohtmx.Raw("{{.}}")}),
+		count.Read(gohtmx.Div{Content: gohtmx.Raw("{{.}}")}),
+		interaction.Trigger().Target(gohtmx.Button{Content: gohtmx.Raw("increment")}),
+	})
+	h, err := p.Build()
+	require.NoError(t, err)
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	require.Equal(t, `<span id="gohtmx_0">0</span>`+
+		`<div id="gohtmx_1">0</div>`+
+		`<button hx-post="/increment" hx-swap="none" type="button">increment</button>`, w.Body.String())
+
+	r = httptest.NewRequest(http.MethodPost, "/increment", nil)
+	r.Header.Set("HX-Request", "true")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	require.Equal(t, `<span id="gohtmx_0" hx-swap-oob="outerHTML">1</span>`+
+		`<div id="gohtmx_1" hx-swap-oob="outerHTML">1</div>`, w.Body.String())
+}
+
+func TestSignalReadAddedLater(t *testing.T) {
+	p := gohtmx.NewPage()
+	count := gohtmx.NewSignal(0)
+	interaction := gohtmx.NewInteraction("increment").Writes(count).Handle(func(r *http.Request) (gohtmx.Data, error) {
+		count.Set(count.Get() + 1)
+		return nil, nil
+	})
+	p.Add(gohtmx.Fragment{
+		interaction,
+		interaction.Trigger().Target(gohtmx.Button{Content: gohtmx.Raw("increment")}),
+	})
+	require.Nil(t, p.Validate())
+	// The Read is only added once the Interaction is already applied.
+	p.Add(count.Read(gohtmx.Span{Content: gohtmx.Raw("{{.}}")}))
+	h, err := p.Build()
+	require.NoError(t, err)
+
+	r := httptest.NewRequest(http.MethodPost, "/increment", nil)
+	r.Header.Set("HX-Request", "true")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	require.Equal(t, `<span id="gohtmx_0" hx-swap-oob="outerHTML">1</span>`, w.Body.String())
+}
+
+func TestSignalSwappedByInteraction(t *testing.T) {
+	p := gohtmx.NewPage()
+	count := gohtmx.NewSignal(0)
+	interaction := gohtmx.NewInteraction("increment").Writes(count).Handle(func(r *http.Request) (gohtmx.Data, error) {
+		count.Set(count.Get() + 1)
+		return nil, nil
+	})
+	p.Add(gohtmx.Fragment{
+		interaction,
+		interaction.Swap().Update(count.Read(gohtmx.Span{Content: gohtmx.Raw("{{.}}")})),
+		count.Read(gohtmx.Div{Content: gohtmx.Raw("{{.}}")}),
+		interaction.Trigger().Target(gohtmx.Button{Content: gohtmx.Raw("increment")}),
+	})
+	h, err := p.Build()
+	require.NoError(t, err)
+
+	// The Read swapped by the Interaction is only swapped in band.
+	r := httptest.NewRequest(http.MethodPost, "/increment", nil)
+	r.Header.Set("HX-Request", "true")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	require.Equal(t, `<div id="gohtmx_1" hx-swap-oob="outerHTML">1</div><span id="gohtmx_0">1</span>`, w.Body.String())
+}
@@ -0,0 +1,220 @@
+package gohtmx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/TheWozard/gohtmx/element"
+	"github.com/TheWozard/gohtmx/websocket"
+)
+
+// NewSocket creates a new Socket with the given name.
+func NewSocket(name string) *Socket {
+	return &Socket{Name: name}
+}
+
+// Socket defines a WebSocket endpoint for bidirectional messaging. See https://htmx.org/extensions/web-sockets/
+// Components are connected to the socket through Connect. Triggers sent over the socket through Trigger.Socket
+// dispatch into their Interaction the same way as a http request, and the rendered swaps are sent back as out of
+// band swaps. Each message passes through the Middleware of the Interaction like a http request, so forms sent over
+// the socket need a CSRFInput when using CSRF. Responses to messages only have a body, any headers set while handling
+// a message, such as cookies or htmx response headers, are dropped. The socket is mounted at its name relative to the
+// current page path. The Socket itself needs to be included in the page as a component. The ws extension is included
+// by any Document with HTMX set.
+type Socket struct {
+	Name string
+
+	connections []*Reference
+	page        *Page
+	mounted     mount
+
+	mu       sync.RWMutex
+	triggers map[string]string
+	handlers map[string]http.Handler
+}
+
+func (s *Socket) Init(p *Page) (element.Element, error) {
+	if s == nil {
+		return nil, nil
+	}
+	s.page = p
+	return element.OnValidate(s.update), nil
+}
+
+// Connect connects the Component to the Socket. Any Triggers sent over the socket need to be within a connected
+// Component.
+func (s *Socket) Connect(c Component) Component {
+	if s == nil || c == nil {
+		return nil
+	}
+	ref := &Reference{Target: c}
+	s.connections = append(s.connections, ref)
+	return ref
+}
+
+// register routes messages sent by the element with the id to the Interaction at the page path.
+func (s *Socket) register(id string, path string, p *Page) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.triggers == nil {
+		s.triggers = map[string]string{}
+	}
+	s.triggers[id] = path
+	p.OnBuild(func(route Route) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.handlers == nil {
+			s.handlers = map[string]http.Handler{}
+		}
+		s.handlers[route.Path] = route.Handler
+	})
+}
+
+// ServeHTTP upgrades the request to a WebSocket and dispatches messages until the connection is closed.
+func (s *Socket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Browsers do not apply the same origin policy to WebSockets, so cross origin connections are rejected.
+	if origin := r.Header.Get("Origin"); origin != "" {
+		u, err := url.Parse(origin)
+		if err != nil || u.Host != r.Host {
+			HandleError(w, r, NewStatusError(http.StatusForbidden, fmt.Errorf("cross origin socket connection")))
+			return
+		}
+	}
+	conn, err := websocket.Upgrade(w, r)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		response, err := s.dispatch(r, message)
+		if err != nil || len(response) == 0 {
+			continue
+		}
+		err = conn.WriteMessage(websocket.OpText, response)
+		if err != nil {
+			return
+		}
+	}
+}
+
+type socketKey struct{}
+
+// dispatch serves a message sent by the htmx ws extension through the handler of the Interaction it was sent for.
+// The message is a JSON object of the values of the sending element, with the htmx request headers under HEADERS.
+func (s *Socket) dispatch(r *http.Request, message []byte) ([]byte, error) {
+	raw := map[string]json.RawMessage{}
+	err := json.Unmarshal(message, &raw)
+	if err != nil {
+		return nil, err
+	}
+	headers := map[string]string{}
+	if h, ok := raw["HEADERS"]; ok {
+		delete(raw, "HEADERS")
+		err = json.Unmarshal(h, &headers)
+		if err != nil {
+			return nil, err
+		}
+	}
+	values := url.Values{}
+	for key, value := range raw {
+		var single string
+		var multiple []string
+		if json.Unmarshal(value, &single) == nil {
+			values.Set(key, single)
+		} else if json.Unmarshal(value, &multiple) == nil {
+			values[key] = multiple
+		} else {
+			values.Set(key, string(value))
+		}
+	}
+
+	s.mu.RLock()
+	path, ok := s.triggers[headers["HX-Trigger"]]
+	var handler http.Handler
+	if ok {
+		handler = s.handlers[strings.SplitN(path, "?", 2)[0]]
+	}
+	s.mu.RUnlock()
+	if handler == nil {
+		return nil, fmt.Errorf("no interaction for trigger '%s'", headers["HX-Trigger"])
+	}
+
+	// Each message is a new request, so values stored in the context by middleware of the upgrade request, such as the
+	// checked CSRF token, are not carried over and the middleware checks the message again. Only the ErrorHandler of
+	// the Page and the cancellation of the connection are kept.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer context.AfterFunc(r.Context(), cancel)()
+	if h, ok := r.Context().Value(errorHandlerKey{}).(ErrorHandler); ok {
+		ctx = context.WithValue(ctx, errorHandlerKey{}, h)
+	}
+	ctx = context.WithValue(ctx, socketKey{}, true)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, path, strings.NewReader(values.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Host = r.Host
+	req.RemoteAddr = r.RemoteAddr
+	for _, cookie := range r.Cookies() {
+		req.AddCookie(cookie)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	w := &bufferedResponse{header: http.Header{}}
+	handler.ServeHTTP(w, withResponse(w, req))
+	return w.body.Bytes(), nil
+}
+
+// update is called during validation.
+func (s *Socket) update() error {
+	if s == nil {
+		return nil
+	}
+	return s.mounted.apply(s.apply)
+}
+
+func (s *Socket) apply() error {
+	page := s.page.AtPath(s.Name)
+	page.useGuards()
+	page.Endpoint(s)
+	page.useExtension("ws")
+	for _, connection := range s.connections {
+		a, err := connection.FindAttrs()
+		if err != nil {
+			return err
+		}
+		a.String("hx-ext", "ws")
+		a.String("ws-connect", page.Path())
+	}
+	return nil
+}
+
+// bufferedResponse collects the body of a response in memory. The status and headers are dropped as socket messages
+// have no equivalent.
+type bufferedResponse struct {
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedResponse) Write(data []byte) (int, error) {
+	return b.body.Write(data)
+}
+
+func (b *bufferedResponse) WriteHeader(int) {}
@@ -0,0 +1,244 @@
+package gohtmx_test
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/TheWozard/gohtmx"
+	"github.com/TheWozard/gohtmx/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSocket(t *testing.T) {
+	p := gohtmx.NewPage()
+	socket := gohtmx.NewSocket("ws")
+	interaction := gohtmx.NewInteraction("send").Handle(func(r *http.Request) (gohtmx.Data, error) {
+		return gohtmx.Data{"message": r.FormValue("message")}, nil
+	})
+	p.Add(gohtmx.Fragment{
+		socket,
+		interaction,
+		socket.Connect(gohtmx.Div{
+			Content: gohtmx.Fragment{
+				interaction.Swap().Update(gohtmx.Div{Content: gohtmx.Raw("{{.message}}")}),
+				interaction.Trigger().Socket(socket).Method(gohtmx.TriggerSubmit).Target(gohtmx.Tag{Name: "form"}),
+			},
+		}),
+	})
+	require.Nil(t, p.Validate())
+	rendered, err := p.Render()
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"/": `{{$r := .request}}{{$hx := .hx}}` +
+			`<div hx-ext="ws" ws-connect="/ws">` +
+			`<div hx-swap-oob="outerHTML" id="gohtmx_0">{{.message}}</div>` +
+			`<form hx-trigger="submit" id="gohtmx_1" ws-send></form>` +
+			`</div>`,
+		"/send": `{{$r := .request}}{{$hx := .hx}}` +
+			`<div hx-swap-oob="outerHTML" id="gohtmx_0">{{.message}}</div>`,
+	}, rendered)
+
+	h, err := p.Build()
+	require.NoError(t, err)
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	conn := dialSocket(t, server.URL+"/ws")
+	defer conn.Close()
+	writeClientFrame(t, conn, `{"message":"hello","HEADERS":{"HX-Request":"true","HX-Trigger":"gohtmx_1"}}`)
+	require.Equal(t, `<div hx-swap-oob="outerHTML" id="gohtmx_0">hello</div>`, readServerFrame(t, conn))
+}
+
+func TestSocketAndRequestTriggers(t *testing.T) {
+	p := gohtmx.NewPage()
+	socket := gohtmx.NewSocket("ws")
+	interaction := gohtmx.NewInteraction("send").Handle(func(r *http.Request) (gohtmx.Data, error) {
+		return gohtmx.Data{"message": r.FormValue("message")}, nil
+	})
+	p.Add(gohtmx.Fragment{
+		socket,
+		interaction,
+		socket.Connect(gohtmx.Div{
+			Content: gohtmx.Fragment{
+				interaction.Swap().Update(gohtmx.Div{Content: gohtmx.Raw("{{.message}}")}),
+				interaction.Trigger().Socket(socket).Method(gohtmx.TriggerSubmit).Target(gohtmx.Tag{Name: "form"}),
+				interaction.Trigger().Method(gohtmx.TriggerClick).Target(gohtmx.Button{}),
+			},
+		}),
+	})
+	require.Nil(t, p.Validate())
+	rendered, err := p.Render()
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"/": `{{$r := .request}}{{$hx := .hx}}` +
+			`<div hx-ext="ws" ws-connect="/ws">` +
+			`<div id="gohtmx_0"{{if $hx.Socket}} hx-swap-oob="outerHTML:#gohtmx_0"{{end}}>{{.message}}</div>` +
+			`<form hx-trigger="submit" id="gohtmx_1" ws-send></form>` +
+			`<button hx-post="/send" hx-swap="outerHTML" hx-target="#gohtmx_0" hx-trigger="click" type="button"></button>` +
+			`</div>`,
+		"/send": `{{$r := .request}}{{$hx := .hx}}` +
+			`<div id="gohtmx_0"{{if $hx.Socket}} hx-swap-oob="outerHTML:#gohtmx_0"{{end}}>{{.message}}</div>`,
+	}, rendered)
+
+	h, err := p.Build()
+	require.NoError(t, err)
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	// Requests swap in band into the target of the trigger.
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/send", strings.NewReader("message=hello"))
+	require.NoError(t, err)
+	req.Header.Set("HX-Request", "true")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, `<div id="gohtmx_0">hello</div>`, string(body))
+
+	conn := dialSocket(t, server.URL+"/ws")
+	defer conn.Close()
+	writeClientFrame(t, conn, `{"message":"hello","HEADERS":{"HX-Request":"true","HX-Trigger":"gohtmx_1"}}`)
+	require.Equal(t, `<div id="gohtmx_0" hx-swap-oob="outerHTML:#gohtmx_0">hello</div>`, readServerFrame(t, conn))
+}
+
+func TestSocketCSRF(t *testing.T) {
+	p := gohtmx.NewPage()
+	p.UseAll(gohtmx.CSRF(gohtmx.CSRFOptions{}))
+	socket := gohtmx.NewSocket("ws")
+	interaction := gohtmx.NewInteraction("send").Handle(func(r *http.Request) (gohtmx.Data, error) {
+		return gohtmx.Data{"message": r.FormValue("message")}, nil
+	})
+	p.Add(gohtmx.Fragment{
+		socket,
+		interaction,
+		socket.Connect(gohtmx.Div{
+			Content: gohtmx.Fragment{
+				interaction.Swap().Update(gohtmx.Div{Content: gohtmx.Raw("{{.message}}")}),
+				interaction.Trigger().Socket(socket).Method(gohtmx.TriggerSubmit).Target(gohtmx.Tag{
+					Name:    "form",
+					Content: gohtmx.CSRFInput{},
+				}),
+			},
+		}),
+	})
+	h, err := p.Build()
+	require.NoError(t, err)
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/")
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	cookies := resp.Cookies()
+	require.Len(t, cookies, 1)
+
+	// The token of the upgrade request does not carry over to the messages.
+	conn := dialSocket(t, server.URL+"/ws", cookies...)
+	defer conn.Close()
+	writeClientFrame(t, conn, `{"message":"hello","HEADERS":{"HX-Request":"true","HX-Trigger":"gohtmx_1"}}`)
+	require.Equal(t, "Forbidden\n", readServerFrame(t, conn))
+	writeClientFrame(t, conn, `{"message":"hello","csrf_token":"`+cookies[0].Value+`",`+
+		`"HEADERS":{"HX-Request":"true","HX-Trigger":"gohtmx_1"}}`)
+	require.Equal(t, `<div hx-swap-oob="outerHTML" id="gohtmx_0">hello</div>`, readServerFrame(t, conn))
+}
+
+func TestSocketControlFrames(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		frame []byte
+	}{
+		{
+			desc:  "fragmented ping",
+			frame: []byte{websocket.OpPing, 0x80, 1, 2, 3, 4},
+		},
+		{
+			desc:  "ping over 125 bytes",
+			frame: append([]byte{0x80 | websocket.OpPing, 0x80 | 126, 0, 126, 1, 2, 3, 4}, make([]byte, 126)...),
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			p := gohtmx.NewPage()
+			p.Add(gohtmx.NewSocket("ws"))
+			h, err := p.Build()
+			require.NoError(t, err)
+			server := httptest.NewServer(h)
+			defer server.Close()
+
+			conn := dialSocket(t, server.URL+"/ws")
+			defer conn.Close()
+			_, err = conn.Write(tC.frame)
+			require.NoError(t, err)
+			frame := make([]byte, 4)
+			_, err = io.ReadFull(conn.reader, frame)
+			require.NoError(t, err)
+			require.Equal(t, []byte{0x80 | websocket.OpClose, 2, 0x03, 0xEA}, frame)
+		})
+	}
+}
+
+type socketConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func dialSocket(t *testing.T, raw string, cookies ...*http.Cookie) socketConn {
+	u, err := url.Parse(raw)
+	require.NoError(t, err)
+	conn, err := net.Dial("tcp", u.Host)
+	require.NoError(t, err)
+	key := "dGhlIHNhbXBsZSBub25jZQ=="
+	headers := ""
+	for _, cookie := range cookies {
+		headers += "Cookie: " + cookie.String() + "\r\n"
+	}
+	_, err = conn.Write([]byte("GET " + u.Path + " HTTP/1.1\r\nHost: " + u.Host + "\r\n" +
+		"Upgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Version: 13\r\n" +
+		"Sec-WebSocket-Key: " + key + "\r\n" + headers + "\r\n"))
+	require.NoError(t, err)
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+	require.Equal(t, websocket.AcceptKey(key), resp.Header.Get("Sec-WebSocket-Accept"))
+	return socketConn{Conn: conn, reader: reader}
+}
+
+func writeClientFrame(t *testing.T, conn socketConn, message string) {
+	mask := []byte{1, 2, 3, 4}
+	frame := []byte{0x80 | websocket.OpText, 0x80 | 126}
+	frame = binary.BigEndian.AppendUint16(frame, uint16(len(message)))
+	frame = append(frame, mask...)
+	for i := range message {
+		frame = append(frame, message[i]^mask[i%4])
+	}
+	_, err := conn.Write(frame)
+	require.NoError(t, err)
+}
+
+func readServerFrame(t *testing.T, conn socketConn) string {
+	header := make([]byte, 2)
+	_, err := io.ReadFull(conn.reader, header)
+	require.NoError(t, err)
+	require.Equal(t, 0x80|websocket.OpText, header[0])
+	length := int(header[1])
+	if length == 126 {
+		extended := make([]byte, 2)
+		_, err = io.ReadFull(conn.reader, extended)
+		require.NoError(t, err)
+		length = int(binary.BigEndian.Uint16(extended))
+	}
+	payload := make([]byte, length)
+	_, err = io.ReadFull(conn.reader, payload)
+	require.NoError(t, err)
+	return string(payload)
+}
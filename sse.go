@@ -0,0 +1,197 @@
+package gohtmx
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/TheWozard/gohtmx/element"
+)
+
+// NewEventStream creates a new EventStream with the given name.
+func NewEventStream(name string) *EventStream {
+	return &EventStream{Name: name}
+}
+
+// EventStream defines a Server-Sent Events endpoint for pushing live updates to the page.
+// See https://htmx.org/extensions/server-sent-events/
+// Components are connected to the stream through Connect, and regions of the page are bound to events through Swap.
+// Publish renders the region bound to an event and pushes it to all connected clients.
+// The stream is mounted at its name relative to the current page path. The EventStream itself needs to be included in
+// the page as a component. The sse extension is included by any Document with HTMX set.
+type EventStream struct {
+	Name string
+
+	connections []*Reference
+	regions     map[string]*Reference
+	page        *Page
+	mounted     mount
+
+	mu        sync.RWMutex
+	templates map[string]TemplateHandler
+	clients   map[chan []byte]struct{}
+}
+
+func (s *EventStream) Init(p *Page) (element.Element, error) {
+	if s == nil {
+		return nil, nil
+	}
+	s.page = p
+	return element.OnValidate(s.update), nil
+}
+
+// Connect connects the Component to the EventStream. Any regions bound through Swap need to be within a connected
+// Component to receive events.
+func (s *EventStream) Connect(c Component) Component {
+	if s == nil || c == nil {
+		return nil
+	}
+	ref := &Reference{Target: c}
+	s.connections = append(s.connections, ref)
+	return ref
+}
+
+// Swap binds the Component to the named event. Each time the event is published, the Component is rendered and
+// replaces itself on all connected clients. Each event can only be bound once.
+func (s *EventStream) Swap(event string, c Component) Component {
+	if s == nil || c == nil {
+		return nil
+	}
+	if s.regions == nil {
+		s.regions = map[string]*Reference{}
+	}
+	if _, ok := s.regions[event]; ok {
+		return RawError{Err: fmt.Errorf("event '%s' already bound", event)}
+	}
+	ref := &Reference{Target: c}
+	s.regions[event] = ref
+	return ref
+}
+
+// Publish renders the Component bound to the event with the data and sends it to all connected clients.
+// There is no request when publishing, so $r is nil for the rendered template.
+func (s *EventStream) Publish(event string, data Data) error {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t, ok := s.templates[event]
+	if !ok {
+		return fmt.Errorf("event '%s' is not bound to a built page", event)
+	}
+	raw, err := t.ExecuteWith(nil, data)
+	if err != nil {
+		return err
+	}
+	message := bytes.NewBufferString("event: " + event + "\n")
+	for _, line := range bytes.Split(raw, []byte("\n")) {
+		message.WriteString("data: ")
+		message.Write(line)
+		message.WriteString("\n")
+	}
+	message.WriteString("\n")
+	for client := range s.clients {
+		// Slow clients miss events rather than blocking the publisher.
+		select {
+		case client <- message.Bytes():
+		default:
+		}
+	}
+	return nil
+}
+
+// ServeHTTP serves the event stream to a single client until the request is canceled.
+func (s *EventStream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		HandleError(w, r, fmt.Errorf("streaming is not supported"))
+		return
+	}
+	// The client is registered before the response starts so no events are missed once the stream is open.
+	client := make(chan []byte, 16)
+	s.mu.Lock()
+	if s.clients == nil {
+		s.clients = map[chan []byte]struct{}{}
+	}
+	s.clients[client] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.clients, client)
+		s.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case message := <-client:
+			_, err := w.Write(message)
+			if err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// update is called during validation.
+func (s *EventStream) update() error {
+	if s == nil {
+		return nil
+	}
+	return s.mounted.apply(s.apply)
+}
+
+func (s *EventStream) apply() error {
+	page := s.page.AtPath(s.Name)
+	page.useGuards()
+	page.Endpoint(s)
+	page.useExtension("sse")
+	for _, connection := range s.connections {
+		a, err := connection.FindAttrs()
+		if err != nil {
+			return err
+		}
+		a.String("hx-ext", "sse")
+		a.String("sse-connect", page.Path())
+	}
+	events := make([]string, 0, len(s.regions))
+	for event := range s.regions {
+		events = append(events, event)
+	}
+	sort.Strings(events)
+	for _, event := range events {
+		event, region := event, s.regions[event]
+		a, err := region.FindAttrs()
+		if err != nil {
+			return err
+		}
+		a.String("sse-swap", event)
+		a.String("hx-swap", string(SwapOuterHTML))
+		// Each region is rendered as its own path so the built template can be used for publishing.
+		regionPage := page.AtPath(event)
+		regionPage.Add(region)
+		regionPage.modify(regionPage.Path(), func(request *Request) {
+			request.Internal = true
+		})
+		regionPage.OnBuild(func(route Route) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if s.templates == nil {
+				s.templates = map[string]TemplateHandler{}
+			}
+			s.templates[event] = route.Template
+		})
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package gohtmx_test
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/TheWozard/gohtmx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventStream(t *testing.T) {
+	p := gohtmx.NewPage()
+	stream := gohtmx.NewEventStream("events")
+	p.Add(gohtmx.Fragment{
+		stream,
+		stream.Connect(gohtmx.Div{
+			Content: stream.Swap("time", gohtmx.Span{
+				Content: gohtmx.Raw(`{{.time}}`),
+			}),
+		}),
+	})
+	require.Nil(t, p.Validate())
+	rendered, err := p.Render()
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"/": `{{$r := .request}}{{$hx := .hx}}` +
+			`<div hx-ext="sse" sse-connect="/events"><span hx-swap="outerHTML" sse-swap="time">{{.time}}</span></div>`,
+		"/events/time": `{{$r := .request}}{{$hx := .hx}}` +
+			`<span hx-swap="outerHTML" sse-swap="time">{{.time}}</span>`,
+	}, rendered)
+
+	h, err := p.Build()
+	require.NoError(t, err)
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/events")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	require.NoError(t, stream.Publish("time", gohtmx.Data{"time": "now"}))
+	reader := bufio.NewReader(resp.Body)
+	lines := []string{}
+	for len(lines) < 2 {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		lines = append(lines, strings.TrimSuffix(line, "\n"))
+	}
+	require.Equal(t, []string{
+		"event: time",
+		`data: <span hx-swap="outerHTML" sse-swap="time">now</span>`,
+	}, lines)
+
+	require.Error(t, stream.Publish("missing", nil))
+}
+
+func TestEventStreamDocument(t *testing.T) {
+	p := gohtmx.NewPage()
+	p.HTMX = gohtmx.NewAssets("/vendor/", fstest.MapFS{
+		"htmx.min.js": {Data: []byte("htmx")},
+		"ext/sse.js":  {Data: []byte("sse")},
+	})
+	stream := gohtmx.NewEventStream("events")
+	p.Add(gohtmx.Document{HTMX: true, Body: gohtmx.Fragment{
+		stream,
+		stream.Connect(gohtmx.Div{Content: stream.Swap("time", gohtmx.Span{})}),
+	}})
+	h, err := p.Build()
+	require.NoError(t, err)
+
+	sse, err := p.HTMX.URL("ext/sse.js")
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Contains(t, w.Body.String(), `<script src="`+sse+`"></script>`)
+
+	// Regions are only rendered for publishing, they are not served.
+	w = httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/events/time", nil)
+	r.Header.Set("HX-Request", "true")
+	h.ServeHTTP(w, r)
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -19,7 +19,7 @@ func (t TWith) Init(p *Page) (element.Element, error) {
 		return t.Content.Init(p)
 	}
 	id := p.Generator.NewID("func")
-	p.Template = p.Template.Funcs(template.FuncMap{id: t.Func})
+	p.funcs(template.FuncMap{id: t.Func})
 	return element.TBlock{
 		Text:       fmt.Sprintf(`with %s $r`, id),
 		IncludeEnd: true,
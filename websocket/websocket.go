@@ -0,0 +1,241 @@
+// Package websocket is a minimal server side implementation of the WebSocket protocol. See RFC 6455.
+// It only supports what is needed to serve htmx ws extension connections: text and binary messages, fragmented
+// messages and the ping, pong and close control frames. Extensions and subprotocols are not supported.
+package websocket
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// acceptGUID is appended to the client key to create the accept key of the handshake.
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// DefaultMaxMessageSize is the default limit of the size of a received message.
+const DefaultMaxMessageSize = 1 << 20
+
+const (
+	OpContinuation byte = 0x0
+	OpText         byte = 0x1
+	OpBinary       byte = 0x2
+	OpClose        byte = 0x8
+	OpPing         byte = 0x9
+	OpPong         byte = 0xA
+)
+
+var (
+	// ErrClosed is returned when reading from a connection closed by the client.
+	ErrClosed = errors.New("websocket: connection closed")
+	// ErrMessageTooLarge is returned when a received message exceeds the MaxMessageSize.
+	ErrMessageTooLarge = errors.New("websocket: message too large")
+	// ErrProtocol is returned when the client violates the protocol. The connection is closed with status 1002.
+	ErrProtocol = errors.New("websocket: protocol error")
+)
+
+// Upgrade performs the WebSocket handshake and takes over the underlying connection of the request.
+// On failure an error response is written and the error is returned.
+func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	if r.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return nil, fmt.Errorf("websocket: method %s not allowed", r.Method)
+	}
+	if !headerContains(r.Header, "Connection", "upgrade") || !headerContains(r.Header, "Upgrade", "websocket") {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return nil, fmt.Errorf("websocket: missing upgrade headers")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, http.StatusText(http.StatusUpgradeRequired), http.StatusUpgradeRequired)
+		return nil, fmt.Errorf("websocket: unsupported version")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return nil, fmt.Errorf("websocket: missing key")
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return nil, fmt.Errorf("websocket: response does not support hijacking")
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, fmt.Errorf("websocket: failed to hijack connection: %w", err)
+	}
+	_, err = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + AcceptKey(key) + "\r\n\r\n")
+	if err == nil {
+		err = rw.Flush()
+	}
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("websocket: failed to write handshake: %w", err)
+	}
+	return &Conn{conn: conn, reader: rw.Reader, MaxMessageSize: DefaultMaxMessageSize}, nil
+}
+
+// AcceptKey returns the Sec-WebSocket-Accept value for the Sec-WebSocket-Key sent by the client.
+func AcceptKey(key string) string {
+	hash := sha1.Sum([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+func headerContains(h http.Header, name, token string) bool {
+	for _, value := range h.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Conn is a server side WebSocket connection. Reads must happen from a single goroutine, writes are safe for
+// concurrent use.
+type Conn struct {
+	// MaxMessageSize limits the size of a received message.
+	MaxMessageSize int64
+
+	conn   net.Conn
+	reader *bufio.Reader
+	mu     sync.Mutex
+}
+
+// ReadMessage reads the next text or binary message, answering any control frames received in between.
+// ErrClosed is returned once the client closes the connection.
+func (c *Conn) ReadMessage() (opcode byte, message []byte, err error) {
+	for {
+		fin, op, payload, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+		switch op {
+		case OpPing:
+			err = c.writeFrame(OpPong, payload)
+			if err != nil {
+				return 0, nil, err
+			}
+			continue
+		case OpPong:
+			continue
+		case OpClose:
+			// Echo the status code of the client as required by the closing handshake.
+			if len(payload) > 2 {
+				payload = payload[:2]
+			}
+			_ = c.writeFrame(OpClose, payload)
+			return 0, nil, ErrClosed
+		case OpContinuation:
+			if opcode == 0 {
+				return 0, nil, fmt.Errorf("websocket: unexpected continuation frame")
+			}
+		case OpText, OpBinary:
+			if opcode != 0 {
+				return 0, nil, fmt.Errorf("websocket: expected continuation frame")
+			}
+			opcode = op
+		default:
+			return 0, nil, fmt.Errorf("websocket: unknown opcode %d", op)
+		}
+		if int64(len(message)+len(payload)) > c.MaxMessageSize {
+			return 0, nil, ErrMessageTooLarge
+		}
+		message = append(message, payload...)
+		if fin {
+			return opcode, message, nil
+		}
+	}
+}
+
+func (c *Conn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	header := make([]byte, 2)
+	_, err = io.ReadFull(c.reader, header)
+	if err != nil {
+		return false, 0, nil, err
+	}
+	fin = header[0]&0x80 != 0
+	opcode = header[0] & 0x0F
+	if header[0]&0x70 != 0 {
+		return false, 0, nil, fmt.Errorf("websocket: unsupported extension bits")
+	}
+	if header[1]&0x80 == 0 {
+		return false, 0, nil, fmt.Errorf("websocket: client frames must be masked")
+	}
+	length := int64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		extended := make([]byte, 2)
+		_, err = io.ReadFull(c.reader, extended)
+		length = int64(binary.BigEndian.Uint16(extended))
+	case 127:
+		extended := make([]byte, 8)
+		_, err = io.ReadFull(c.reader, extended)
+		length = int64(binary.BigEndian.Uint64(extended) & (1<<63 - 1))
+	}
+	if err != nil {
+		return false, 0, nil, err
+	}
+	// Control frames cannot be fragmented and are limited to 125 bytes. See RFC 6455 section 5.5.
+	if opcode&0x8 != 0 && (!fin || length > 125) {
+		_ = c.writeFrame(OpClose, []byte{0x03, 0xEA})
+		return false, 0, nil, fmt.Errorf("%w: invalid control frame", ErrProtocol)
+	}
+	if length > c.MaxMessageSize {
+		return false, 0, nil, ErrMessageTooLarge
+	}
+	mask := make([]byte, 4)
+	_, err = io.ReadFull(c.reader, mask)
+	if err != nil {
+		return false, 0, nil, err
+	}
+	payload = make([]byte, length)
+	_, err = io.ReadFull(c.reader, payload)
+	if err != nil {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return fin, opcode, payload, nil
+}
+
+// WriteMessage writes the message as a single frame. Server frames are never masked.
+func (c *Conn) WriteMessage(opcode byte, message []byte) error {
+	return c.writeFrame(opcode, message)
+}
+
+func (c *Conn) writeFrame(opcode byte, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	header := []byte{0x80 | opcode}
+	switch length := len(payload); {
+	case length < 126:
+		header = append(header, byte(length))
+	case length <= 0xFFFF:
+		header = append(header, 126)
+		header = binary.BigEndian.AppendUint16(header, uint16(length))
+	default:
+		header = append(header, 127)
+		header = binary.BigEndian.AppendUint64(header, uint64(length))
+	}
+	_, err := c.conn.Write(append(header, payload...))
+	return err
+}
+
+// Close sends a normal closure frame and closes the underlying connection.
+func (c *Conn) Close() error {
+	_ = c.writeFrame(OpClose, []byte{0x03, 0xE8})
+	return c.conn.Close()
+}